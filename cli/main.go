@@ -1,10 +1,11 @@
 package main
 
 import (
-	"bufio"
+	"encoding/json"
+	"flag"
 	"fmt"
+	"glass/language/diagnostic"
 	"glass/language/evaluator"
-	"glass/language/lexer"
 	"glass/language/object"
 	"glass/language/parser"
 	"log"
@@ -14,66 +15,97 @@ import (
 
 func main() {
 	if len(os.Args) < 3 {
-		fmt.Println("Usage: glass <command> <filename>")
-		return
+		exitWithUsage()
 	}
 
 	command := os.Args[1]
-	filename := os.Args[2]
+	if command != "run" && command != "check" {
+		fmt.Fprintln(os.Stderr, "Unknown command:", command)
+		exitWithUsage()
+	}
+
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	checkedArithmetic := flags.Bool("checked", false, "report integer overflows as errors")
+	format := flags.String("format", "text", "diagnostics format, text or json")
+
+	// Parsing stops at the first argument that is not a flag, so flags placed
+	// after the filename are parsed from the arguments left over
+	filenames := []string{}
+	arguments := os.Args[2:]
+	for {
+		flags.Parse(arguments)
+		if flags.NArg() == 0 {
+			break
+		}
+
+		filenames = append(filenames, flags.Arg(0))
+		arguments = flags.Args()[1:]
+	}
+
+	if len(filenames) != 1 {
+		exitWithUsage()
+	}
+
+	if *format != "text" && *format != "json" {
+		fmt.Fprintln(os.Stderr, "Unknown format:", *format)
+		exitWithUsage()
+	}
+
+	filename := filenames[0]
 
 	fullpath, absError := filepath.Abs(filename)
 	if absError != nil {
 		log.Fatal("Error getting absolute path:", absError)
-		return
 	}
 
 	runDirectory := filepath.Dir(fullpath)
 
-	if command == "run" {
-
-		// File handling
-		file, openError := os.Open(filename)
-		if openError != nil {
-			log.Fatal("Error reading file:", openError)
-			return
-		}
-
-		defer file.Close()
-
-		scanner := bufio.NewScanner(file)
-		if !scanner.Scan() {
-			fmt.Println("File is empty.")
-		}
+	program, diagnostics, readError := parser.GetParsedFile(filename)
+	if readError != nil {
+		reportDiagnostics([]*diagnostic.Diagnostic{diagnostic.FromFileError(filename, readError)}, *format)
+		os.Exit(1)
+	}
 
-		firstLine := scanner.Text()
+	if len(diagnostics) > 0 {
+		reportDiagnostics(diagnostics, *format)
+		os.Exit(1)
+	}
 
-		// Interpreting
-		programEnvironment := object.NewProgramEnvironment(runDirectory)
-		moduleEnvironment := object.NewEnvironment(filename, programEnvironment)
-		lexer := lexer.New(firstLine, func() (string, bool) {
-			if !scanner.Scan() {
-				return "", true
-			}
+	// Checking stops once the file is parsed
+	if command == "check" {
+		return
+	}
 
-			return scanner.Text(), false
-		})
+	// Interpreting
+	programEnvironment := object.NewProgramEnvironment(runDirectory)
+	programEnvironment.CheckedArithmetic = *checkedArithmetic
+	moduleEnvironment := object.NewEnvironment(filename, programEnvironment)
 
-		parser := parser.New(lexer)
-		program := parser.ParseProgram()
+	result := evaluator.Evaluate(program, moduleEnvironment)
+	if err, ok := result.(*object.Error); ok {
+		reportDiagnostics([]*diagnostic.Diagnostic{diagnostic.FromError(err)}, *format)
+		os.Exit(1)
+	}
+}
 
-		errors := parser.GetErrors()
-		if len(errors) > 0 {
-			for _, err := range errors {
-				log.Print(err)
-			}
-			return
+// Writes diagnostics to stderr, as rendered snippets or one JSON object per line
+func reportDiagnostics(diagnostics []*diagnostic.Diagnostic, format string) {
+	if format == "json" {
+		encoder := json.NewEncoder(os.Stderr)
+		for _, reported := range diagnostics {
+			encoder.Encode(reported)
 		}
 
-		result := evaluator.Evaluate(program, moduleEnvironment)
-		if result != nil && result.GetType() == object.ERROR_OBJECT {
-			log.Fatal(result.Inspect())
-		}
-	} else {
-		fmt.Println("Unknown command:", command)
+		return
 	}
+
+	renderer := diagnostic.NewRenderer(diagnostic.IsColorTerminal(os.Stderr))
+	for _, reported := range diagnostics {
+		fmt.Fprint(os.Stderr, renderer.Render(reported))
+	}
+}
+
+func exitWithUsage() {
+	fmt.Fprintln(os.Stderr, "Usage: glass <run|check> [options] <filename>")
+	os.Exit(2)
 }
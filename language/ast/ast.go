@@ -10,6 +10,7 @@ import (
 // Interfaces
 type Node interface {
 	TokenLiteral() string
+	GetToken() token.Token // Token the node was parsed from, for error positions
 	String() string
 }
 
@@ -23,6 +24,36 @@ type Expression interface {
 	expressionNode()
 }
 
+// Returns the first token of an expression, which operators and chains keep further right
+func StartToken(expression Expression) token.Token {
+	switch expression := expression.(type) {
+
+	case *CallExpression:
+		return StartToken(expression.Function)
+
+	case *IndexExpression:
+		return StartToken(expression.Left)
+
+	case *AccessExpression:
+		return StartToken(expression.Accessor)
+
+	case *InfixExpression:
+		return StartToken(expression.LeftExpression)
+
+	case *LogicalExpression:
+		return StartToken(expression.LeftExpression)
+
+	case *ConditionalExpression:
+		return StartToken(expression.Condition)
+
+	case *AssignmentExpression:
+		return StartToken(expression.Target)
+
+	}
+
+	return expression.GetToken()
+}
+
 // Program
 type Program struct {
 	Statements []Statement
@@ -36,6 +67,11 @@ func (program *Program) TokenLiteral() string {
 	return ""
 }
 
+// Programs have no token of their own
+func (program *Program) GetToken() token.Token {
+	return token.Token{}
+}
+
 func (program *Program) String() string {
 	var buffer bytes.Buffer
 
@@ -52,23 +88,25 @@ type Identifier struct {
 	Value string
 }
 
-func (identifier *Identifier) expressionNode()      {}
-func (identifier *Identifier) TokenLiteral() string { return identifier.Token.Literal }
-func (identifier *Identifier) String() string       { return identifier.Value }
+func (identifier *Identifier) expressionNode()       {}
+func (identifier *Identifier) TokenLiteral() string  { return identifier.Token.Literal }
+func (identifier *Identifier) GetToken() token.Token { return identifier.Token }
+func (identifier *Identifier) String() string        { return identifier.Value }
 
 // Let statement
 type LetStatement struct {
 	Token      token.Token
-	Identifier *Identifier
+	Pattern    Expression // *Identifier, *ArrayPattern or *HashPattern
 	Expression Expression
 }
 
-func (statement *LetStatement) statementNode()       {}
-func (statement *LetStatement) TokenLiteral() string { return statement.Token.Literal }
+func (statement *LetStatement) statementNode()        {}
+func (statement *LetStatement) TokenLiteral() string  { return statement.Token.Literal }
+func (statement *LetStatement) GetToken() token.Token { return statement.Token }
 func (statement *LetStatement) String() string {
 	var buffer bytes.Buffer
 	buffer.WriteString(statement.TokenLiteral() + " ")
-	buffer.WriteString(statement.Identifier.String())
+	buffer.WriteString(statement.Pattern.String())
 	buffer.WriteString(" = ")
 	if statement.Expression != nil {
 		buffer.WriteString(statement.Expression.String())
@@ -83,8 +121,9 @@ type ReturnStatement struct {
 	Expression Expression
 }
 
-func (statement *ReturnStatement) statementNode()       {}
-func (statement *ReturnStatement) TokenLiteral() string { return statement.Token.Literal }
+func (statement *ReturnStatement) statementNode()        {}
+func (statement *ReturnStatement) TokenLiteral() string  { return statement.Token.Literal }
+func (statement *ReturnStatement) GetToken() token.Token { return statement.Token }
 func (statement *ReturnStatement) String() string {
 	var buffer bytes.Buffer
 	buffer.WriteString(statement.TokenLiteral() + " ")
@@ -95,14 +134,65 @@ func (statement *ReturnStatement) String() string {
 	return buffer.String()
 }
 
+// Throw statement
+type ThrowStatement struct {
+	Token      token.Token
+	Expression Expression
+}
+
+func (statement *ThrowStatement) statementNode()        {}
+func (statement *ThrowStatement) TokenLiteral() string  { return statement.Token.Literal }
+func (statement *ThrowStatement) GetToken() token.Token { return statement.Token }
+func (statement *ThrowStatement) String() string {
+	return statement.TokenLiteral() + " " + statement.Expression.String() + ";"
+}
+
+// Try statement, requiring at least a catch or a finally block
+type TryStatement struct {
+	Token          token.Token
+	Body           *BlockStatement
+	CatchParameter *Identifier // Optional, even with a catch block
+	Catch          *BlockStatement
+	Finally        *BlockStatement
+}
+
+func (statement *TryStatement) statementNode()        {}
+func (statement *TryStatement) TokenLiteral() string  { return statement.Token.Literal }
+func (statement *TryStatement) GetToken() token.Token { return statement.Token }
+func (statement *TryStatement) String() string {
+	var buffer bytes.Buffer
+	buffer.WriteString("try { ")
+	buffer.WriteString(statement.Body.String())
+	buffer.WriteString(" }")
+
+	if statement.Catch != nil {
+		buffer.WriteString(" catch ")
+		if statement.CatchParameter != nil {
+			buffer.WriteString("(" + statement.CatchParameter.String() + ") ")
+		}
+		buffer.WriteString("{ ")
+		buffer.WriteString(statement.Catch.String())
+		buffer.WriteString(" }")
+	}
+
+	if statement.Finally != nil {
+		buffer.WriteString(" finally { ")
+		buffer.WriteString(statement.Finally.String())
+		buffer.WriteString(" }")
+	}
+
+	return buffer.String()
+}
+
 // Expression statement
 type ExpressionStatement struct {
 	Token      token.Token
 	Expression Expression
 }
 
-func (statement *ExpressionStatement) statementNode()       {}
-func (statement *ExpressionStatement) TokenLiteral() string { return statement.Token.Literal }
+func (statement *ExpressionStatement) statementNode()        {}
+func (statement *ExpressionStatement) TokenLiteral() string  { return statement.Token.Literal }
+func (statement *ExpressionStatement) GetToken() token.Token { return statement.Token }
 func (statement *ExpressionStatement) String() string {
 	if statement.Expression != nil {
 		return statement.Expression.String()
@@ -116,8 +206,9 @@ type BlockStatement struct {
 	Statements []Statement
 }
 
-func (statement *BlockStatement) statementNode()       {}
-func (statement *BlockStatement) TokenLiteral() string { return statement.Token.Literal }
+func (statement *BlockStatement) statementNode()        {}
+func (statement *BlockStatement) TokenLiteral() string  { return statement.Token.Literal }
+func (statement *BlockStatement) GetToken() token.Token { return statement.Token }
 func (statement *BlockStatement) String() string {
 	var buffer bytes.Buffer
 	for _, statement := range statement.Statements {
@@ -133,8 +224,9 @@ type ImportStatement struct {
 	Path       string
 }
 
-func (statement *ImportStatement) statementNode()       {}
-func (statement *ImportStatement) TokenLiteral() string { return statement.Token.Literal }
+func (statement *ImportStatement) statementNode()        {}
+func (statement *ImportStatement) TokenLiteral() string  { return statement.Token.Literal }
+func (statement *ImportStatement) GetToken() token.Token { return statement.Token }
 func (statement *ImportStatement) String() string {
 	return "import " + statement.Identifier.Value + " " + statement.Path
 }
@@ -145,21 +237,157 @@ type ExportStatement struct {
 	Identifier *Identifier
 }
 
-func (statement *ExportStatement) statementNode()       {}
-func (statement *ExportStatement) TokenLiteral() string { return statement.Token.Literal }
+func (statement *ExportStatement) statementNode()        {}
+func (statement *ExportStatement) TokenLiteral() string  { return statement.Token.Literal }
+func (statement *ExportStatement) GetToken() token.Token { return statement.Token }
 func (statement *ExportStatement) String() string {
 	return "export " + statement.Identifier.String()
 }
 
+// While statement
+type WhileStatement struct {
+	Token     token.Token
+	Label     *Identifier
+	Condition Expression
+	Body      *BlockStatement
+}
+
+func (statement *WhileStatement) statementNode()        {}
+func (statement *WhileStatement) TokenLiteral() string  { return statement.Token.Literal }
+func (statement *WhileStatement) GetToken() token.Token { return statement.Token }
+func (statement *WhileStatement) String() string {
+	var buffer bytes.Buffer
+	writeLoopLabel(&buffer, statement.Label)
+	buffer.WriteString("while (")
+	buffer.WriteString(statement.Condition.String())
+	buffer.WriteString(") { ")
+	buffer.WriteString(statement.Body.String())
+	buffer.WriteString(" }")
+	return buffer.String()
+}
+
+// For statement
+type ForStatement struct {
+	Token          token.Token
+	Label          *Identifier
+	Initialization Statement
+	Condition      Expression
+	Update         Statement
+	Body           *BlockStatement
+}
+
+func (statement *ForStatement) statementNode()        {}
+func (statement *ForStatement) TokenLiteral() string  { return statement.Token.Literal }
+func (statement *ForStatement) GetToken() token.Token { return statement.Token }
+func (statement *ForStatement) String() string {
+	var buffer bytes.Buffer
+	writeLoopLabel(&buffer, statement.Label)
+	buffer.WriteString("for (")
+
+	// Let statements already end with their semicolon
+	initialization := ""
+	if statement.Initialization != nil {
+		initialization = statement.Initialization.String()
+	}
+	buffer.WriteString(strings.TrimSuffix(initialization, ";"))
+	buffer.WriteString("; ")
+	if statement.Condition != nil {
+		buffer.WriteString(statement.Condition.String())
+	}
+	buffer.WriteString("; ")
+	if statement.Update != nil {
+		buffer.WriteString(statement.Update.String())
+	}
+	buffer.WriteString(") { ")
+	buffer.WriteString(statement.Body.String())
+	buffer.WriteString(" }")
+	return buffer.String()
+}
+
+// For in statement
+type ForInStatement struct {
+	Token      token.Token
+	Label      *Identifier
+	Identifier *Identifier
+	Iterable   Expression
+	Body       *BlockStatement
+}
+
+func (statement *ForInStatement) statementNode()        {}
+func (statement *ForInStatement) TokenLiteral() string  { return statement.Token.Literal }
+func (statement *ForInStatement) GetToken() token.Token { return statement.Token }
+func (statement *ForInStatement) String() string {
+	var buffer bytes.Buffer
+	writeLoopLabel(&buffer, statement.Label)
+	buffer.WriteString("for (")
+	buffer.WriteString(statement.Identifier.String())
+	buffer.WriteString(" in ")
+	buffer.WriteString(statement.Iterable.String())
+	buffer.WriteString(") { ")
+	buffer.WriteString(statement.Body.String())
+	buffer.WriteString(" }")
+	return buffer.String()
+}
+
+func writeLoopLabel(buffer *bytes.Buffer, label *Identifier) {
+	if label != nil {
+		buffer.WriteString(label.String() + ": ")
+	}
+}
+
+// Break statement
+type BreakStatement struct {
+	Token token.Token
+	Label *Identifier
+}
+
+func (statement *BreakStatement) statementNode()        {}
+func (statement *BreakStatement) TokenLiteral() string  { return statement.Token.Literal }
+func (statement *BreakStatement) GetToken() token.Token { return statement.Token }
+func (statement *BreakStatement) String() string {
+	if statement.Label != nil {
+		return "break " + statement.Label.String() + ";"
+	}
+	return "break;"
+}
+
+// Continue statement
+type ContinueStatement struct {
+	Token token.Token
+	Label *Identifier
+}
+
+func (statement *ContinueStatement) statementNode()        {}
+func (statement *ContinueStatement) TokenLiteral() string  { return statement.Token.Literal }
+func (statement *ContinueStatement) GetToken() token.Token { return statement.Token }
+func (statement *ContinueStatement) String() string {
+	if statement.Label != nil {
+		return "continue " + statement.Label.String() + ";"
+	}
+	return "continue;"
+}
+
 // Integer literal
 type IntegerLiteral struct {
 	Token token.Token
 	Value int64
 }
 
-func (integer *IntegerLiteral) expressionNode()      {}
-func (integer *IntegerLiteral) TokenLiteral() string { return integer.Token.Literal }
-func (integer *IntegerLiteral) String() string       { return integer.Token.Literal }
+func (integer *IntegerLiteral) expressionNode()       {}
+func (integer *IntegerLiteral) TokenLiteral() string  { return integer.Token.Literal }
+func (integer *IntegerLiteral) GetToken() token.Token { return integer.Token }
+func (integer *IntegerLiteral) String() string        { return integer.Token.Literal }
+
+// Float literal
+type FloatLiteral struct {
+	Token token.Token
+	Value float64
+}
+
+func (float *FloatLiteral) expressionNode()       {}
+func (float *FloatLiteral) TokenLiteral() string  { return float.Token.Literal }
+func (float *FloatLiteral) GetToken() token.Token { return float.Token }
+func (float *FloatLiteral) String() string        { return float.Token.Literal }
 
 // String literal
 type StringLiteral struct {
@@ -167,9 +395,44 @@ type StringLiteral struct {
 	Value string
 }
 
-func (literal *StringLiteral) expressionNode()      {}
-func (literal *StringLiteral) TokenLiteral() string { return literal.Token.Literal }
-func (literal *StringLiteral) String() string       { return literal.Token.Literal }
+func (literal *StringLiteral) expressionNode()       {}
+func (literal *StringLiteral) TokenLiteral() string  { return literal.Token.Literal }
+func (literal *StringLiteral) GetToken() token.Token { return literal.Token }
+func (literal *StringLiteral) String() string        { return literal.Token.Literal }
+
+// Template literal, alternating string literals and interpolations
+type TemplateLiteral struct {
+	Token token.Token
+	Parts []Expression
+}
+
+func (literal *TemplateLiteral) expressionNode()       {}
+func (literal *TemplateLiteral) TokenLiteral() string  { return literal.Token.Literal }
+func (literal *TemplateLiteral) GetToken() token.Token { return literal.Token }
+func (literal *TemplateLiteral) String() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\"")
+	for _, part := range literal.Parts {
+		buffer.WriteString(part.String())
+	}
+	buffer.WriteString("\"")
+
+	return buffer.String()
+}
+
+// Interpolation
+type Interpolation struct {
+	Token      token.Token // First token of the expression
+	Expression Expression
+}
+
+func (interpolation *Interpolation) expressionNode()       {}
+func (interpolation *Interpolation) TokenLiteral() string  { return interpolation.Token.Literal }
+func (interpolation *Interpolation) GetToken() token.Token { return interpolation.Token }
+func (interpolation *Interpolation) String() string {
+	return "${" + interpolation.Expression.String() + "}"
+}
 
 // Prefix operator
 type PrefixExpression struct {
@@ -178,8 +441,9 @@ type PrefixExpression struct {
 	Expression Expression
 }
 
-func (expression *PrefixExpression) expressionNode()      {}
-func (expression *PrefixExpression) TokenLiteral() string { return expression.Token.Literal }
+func (expression *PrefixExpression) expressionNode()       {}
+func (expression *PrefixExpression) TokenLiteral() string  { return expression.Token.Literal }
+func (expression *PrefixExpression) GetToken() token.Token { return expression.Token }
 func (expression *PrefixExpression) String() string {
 	var buffer bytes.Buffer
 
@@ -199,8 +463,9 @@ type InfixExpression struct {
 	RightExpression Expression
 }
 
-func (expression *InfixExpression) expressionNode()      {}
-func (expression *InfixExpression) TokenLiteral() string { return expression.Token.Literal }
+func (expression *InfixExpression) expressionNode()       {}
+func (expression *InfixExpression) TokenLiteral() string  { return expression.Token.Literal }
+func (expression *InfixExpression) GetToken() token.Token { return expression.Token }
 func (expression *InfixExpression) String() string {
 	var buffer bytes.Buffer
 
@@ -213,48 +478,143 @@ func (expression *InfixExpression) String() string {
 	return buffer.String()
 }
 
+// Logical expression, only evaluating its right expression when needed
+type LogicalExpression struct {
+	Token           token.Token
+	LeftExpression  Expression
+	Operator        string
+	RightExpression Expression
+}
+
+func (expression *LogicalExpression) expressionNode()       {}
+func (expression *LogicalExpression) TokenLiteral() string  { return expression.Token.Literal }
+func (expression *LogicalExpression) GetToken() token.Token { return expression.Token }
+func (expression *LogicalExpression) String() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("(")
+	buffer.WriteString(expression.LeftExpression.String())
+	buffer.WriteString(" " + expression.Operator + " ")
+	buffer.WriteString(expression.RightExpression.String())
+	buffer.WriteString(")")
+
+	return buffer.String()
+}
+
+// Conditional expression
+type ConditionalExpression struct {
+	Token       token.Token // QUESTION '?' token
+	Condition   Expression
+	Consequence Expression
+	Alternative Expression
+}
+
+func (expression *ConditionalExpression) expressionNode()       {}
+func (expression *ConditionalExpression) TokenLiteral() string  { return expression.Token.Literal }
+func (expression *ConditionalExpression) GetToken() token.Token { return expression.Token }
+func (expression *ConditionalExpression) String() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("(")
+	buffer.WriteString(expression.Condition.String())
+	buffer.WriteString(" ? ")
+	buffer.WriteString(expression.Consequence.String())
+	buffer.WriteString(" : ")
+	buffer.WriteString(expression.Alternative.String())
+	buffer.WriteString(")")
+
+	return buffer.String()
+}
+
+// Assignment expression
+type AssignmentExpression struct {
+	Token    token.Token
+	Target   Expression
+	Operator string
+	Value    Expression
+}
+
+func (expression *AssignmentExpression) expressionNode()       {}
+func (expression *AssignmentExpression) TokenLiteral() string  { return expression.Token.Literal }
+func (expression *AssignmentExpression) GetToken() token.Token { return expression.Token }
+func (expression *AssignmentExpression) String() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("(")
+	buffer.WriteString(expression.Target.String())
+	buffer.WriteString(" " + expression.Operator + " ")
+	buffer.WriteString(expression.Value.String())
+	buffer.WriteString(")")
+
+	return buffer.String()
+}
+
 // Boolean
 type Boolean struct {
 	Token token.Token
 	Value bool
 }
 
-func (boolean *Boolean) expressionNode()      {}
-func (boolean *Boolean) TokenLiteral() string { return boolean.Token.Literal }
-func (boolean *Boolean) String() string       { return boolean.Token.Literal }
+func (boolean *Boolean) expressionNode()       {}
+func (boolean *Boolean) TokenLiteral() string  { return boolean.Token.Literal }
+func (boolean *Boolean) GetToken() token.Token { return boolean.Token }
+func (boolean *Boolean) String() string        { return boolean.Token.Literal }
+
+// Null
+type NullLiteral struct {
+	Token token.Token
+}
+
+func (null *NullLiteral) expressionNode()       {}
+func (null *NullLiteral) TokenLiteral() string  { return null.Token.Literal }
+func (null *NullLiteral) GetToken() token.Token { return null.Token }
+func (null *NullLiteral) String() string        { return null.Token.Literal }
 
 // If expression
 type IfExpression struct {
 	Token       token.Token
 	Condition   Expression
 	Consequence *BlockStatement
-	Alternative *BlockStatement
+	Alternative Node // *BlockStatement, or *IfExpression for "else if"
 }
 
-func (statement *IfExpression) expressionNode()      {}
-func (statement *IfExpression) TokenLiteral() string { return statement.Token.Literal }
+func (statement *IfExpression) expressionNode()       {}
+func (statement *IfExpression) TokenLiteral() string  { return statement.Token.Literal }
+func (statement *IfExpression) GetToken() token.Token { return statement.Token }
 func (statement *IfExpression) String() string {
 	var buffer bytes.Buffer
-	buffer.WriteString("if")
+	buffer.WriteString("if (")
 	buffer.WriteString(statement.Condition.String())
-	buffer.WriteString(" ")
+	buffer.WriteString(") { ")
 	buffer.WriteString(statement.Consequence.String())
-	if statement.Alternative != nil {
-		buffer.WriteString("else ")
-		buffer.WriteString(statement.Alternative.String())
+	buffer.WriteString(" }")
+
+	switch alternative := statement.Alternative.(type) {
+
+	case *IfExpression:
+		buffer.WriteString(" else ")
+		buffer.WriteString(alternative.String())
+
+	case *BlockStatement:
+		buffer.WriteString(" else { ")
+		buffer.WriteString(alternative.String())
+		buffer.WriteString(" }")
+
 	}
+
 	return buffer.String()
 }
 
 // Function
 type Function struct {
 	Token      token.Token
-	Parameters []*Identifier
+	Parameters []*Parameter
 	Body       *BlockStatement
 }
 
-func (function *Function) expressionNode()      {}
-func (function *Function) TokenLiteral() string { return function.Token.Literal }
+func (function *Function) expressionNode()       {}
+func (function *Function) TokenLiteral() string  { return function.Token.Literal }
+func (function *Function) GetToken() token.Token { return function.Token }
 func (function *Function) String() string {
 	var buffer bytes.Buffer
 	parameters := []string{}
@@ -270,6 +630,28 @@ func (function *Function) String() string {
 	return buffer.String()
 }
 
+type Parameter struct {
+	Token   token.Token
+	Pattern Expression // *Identifier, *ArrayPattern or *HashPattern
+	Default Expression // Evaluated when no argument is given
+	Rest    bool       // Collects the remaining arguments in an array
+}
+
+func (parameter *Parameter) String() string {
+	switch {
+
+	case parameter.Rest:
+		return "..." + parameter.Pattern.String()
+
+	case parameter.Default != nil:
+		return parameter.Pattern.String() + " = " + parameter.Default.String()
+
+	default:
+		return parameter.Pattern.String()
+
+	}
+}
+
 // Call expression
 type CallExpression struct {
 	Token     token.Token
@@ -277,8 +659,9 @@ type CallExpression struct {
 	Arguments []Expression
 }
 
-func (expression *CallExpression) expressionNode()      {}
-func (expression *CallExpression) TokenLiteral() string { return expression.Token.Literal }
+func (expression *CallExpression) expressionNode()       {}
+func (expression *CallExpression) TokenLiteral() string  { return expression.Token.Literal }
+func (expression *CallExpression) GetToken() token.Token { return expression.Token }
 func (expression *CallExpression) String() string {
 	var buffer bytes.Buffer
 	args := []string{}
@@ -293,14 +676,40 @@ func (expression *CallExpression) String() string {
 	return buffer.String()
 }
 
+// Spread, expanding an array into call arguments or array elements
+type SpreadExpression struct {
+	Token      token.Token // ELLIPSIS '...' token
+	Expression Expression
+}
+
+func (expression *SpreadExpression) expressionNode()       {}
+func (expression *SpreadExpression) TokenLiteral() string  { return expression.Token.Literal }
+func (expression *SpreadExpression) GetToken() token.Token { return expression.Token }
+func (expression *SpreadExpression) String() string        { return "..." + expression.Expression.String() }
+
+// Named argument, as in "connect(host, port: 80)"
+type NamedArgument struct {
+	Token token.Token // IDENTIFIER token
+	Name  *Identifier
+	Value Expression
+}
+
+func (argument *NamedArgument) expressionNode()       {}
+func (argument *NamedArgument) TokenLiteral() string  { return argument.Token.Literal }
+func (argument *NamedArgument) GetToken() token.Token { return argument.Token }
+func (argument *NamedArgument) String() string {
+	return argument.Name.String() + ": " + argument.Value.String()
+}
+
 // Array
 type ArrayLiteral struct {
 	Token    token.Token // LBRACKET '[' token
 	Elements []Expression
 }
 
-func (array *ArrayLiteral) expressionNode()      {}
-func (array *ArrayLiteral) TokenLiteral() string { return array.Token.Literal }
+func (array *ArrayLiteral) expressionNode()       {}
+func (array *ArrayLiteral) TokenLiteral() string  { return array.Token.Literal }
+func (array *ArrayLiteral) GetToken() token.Token { return array.Token }
 func (array *ArrayLiteral) String() string {
 	var buffer bytes.Buffer
 	elements := []string{}
@@ -316,17 +725,22 @@ func (array *ArrayLiteral) String() string {
 
 // Index
 type IndexExpression struct {
-	Token token.Token // LBRACKET '[' token
-	Left  Expression
-	Index Expression
+	Token    token.Token // LBRACKET '[' or QUESTION_DOT '?.' token
+	Left     Expression
+	Index    Expression
+	Optional bool
 }
 
-func (expression *IndexExpression) expressionNode()      {}
-func (expression *IndexExpression) TokenLiteral() string { return expression.Token.Literal }
+func (expression *IndexExpression) expressionNode()       {}
+func (expression *IndexExpression) TokenLiteral() string  { return expression.Token.Literal }
+func (expression *IndexExpression) GetToken() token.Token { return expression.Token }
 func (expression *IndexExpression) String() string {
 	var buffer bytes.Buffer
 	buffer.WriteString("(")
 	buffer.WriteString(expression.Left.String())
+	if expression.Optional {
+		buffer.WriteString("?.")
+	}
 	buffer.WriteString("[")
 	buffer.WriteString(expression.Index.String())
 	buffer.WriteString("])")
@@ -339,8 +753,9 @@ type HashLiteral struct {
 	Pairs map[Expression]Expression
 }
 
-func (hash *HashLiteral) expressionNode()      {}
-func (hash *HashLiteral) TokenLiteral() string { return hash.Token.Literal }
+func (hash *HashLiteral) expressionNode()       {}
+func (hash *HashLiteral) TokenLiteral() string  { return hash.Token.Literal }
+func (hash *HashLiteral) GetToken() token.Token { return hash.Token }
 func (hash *HashLiteral) String() string {
 	var buffer bytes.Buffer
 	pairs := []string{}
@@ -358,15 +773,113 @@ func (hash *HashLiteral) String() string {
 type AccessExpression struct {
 	Token    token.Token
 	Accessor Expression
-	Accessed Expression
+	Accessed *Identifier
+	Optional bool
 }
 
-func (expression *AccessExpression) expressionNode()      {}
-func (expression *AccessExpression) TokenLiteral() string { return expression.Token.Literal }
+func (expression *AccessExpression) expressionNode()       {}
+func (expression *AccessExpression) TokenLiteral() string  { return expression.Token.Literal }
+func (expression *AccessExpression) GetToken() token.Token { return expression.Token }
 func (expression *AccessExpression) String() string {
+	operator := "."
+	if expression.Optional {
+		operator = "?."
+	}
+
 	return fmt.Sprintf(
-		"(%s).(%s)",
+		"(%s)%s(%s)",
 		expression.Accessor.String(),
+		operator,
 		expression.Accessed.String(),
 	)
 }
+
+// Match expression
+type MatchExpression struct {
+	Token   token.Token // MATCH token
+	Subject Expression
+	Arms    []*MatchArm
+}
+
+func (expression *MatchExpression) expressionNode()       {}
+func (expression *MatchExpression) TokenLiteral() string  { return expression.Token.Literal }
+func (expression *MatchExpression) GetToken() token.Token { return expression.Token }
+func (expression *MatchExpression) String() string {
+	var buffer bytes.Buffer
+	arms := []string{}
+	for _, arm := range expression.Arms {
+		arms = append(arms, arm.String())
+	}
+
+	buffer.WriteString("match (")
+	buffer.WriteString(expression.Subject.String())
+	buffer.WriteString(") { ")
+	buffer.WriteString(strings.Join(arms, ", "))
+	buffer.WriteString(" }")
+	return buffer.String()
+}
+
+type MatchArm struct {
+	Token   token.Token // FAT_ARROW '=>' token
+	Pattern Expression
+	Body    Node // *BlockStatement or Expression
+}
+
+func (arm *MatchArm) String() string {
+	if body, ok := arm.Body.(*BlockStatement); ok {
+		return arm.Pattern.String() + " => { " + body.String() + " }"
+	}
+
+	return arm.Pattern.String() + " => " + arm.Body.String()
+}
+
+// Array pattern, matching arrays of the same length element by element
+type ArrayPattern struct {
+	Token    token.Token // LBRACKET '[' token
+	Elements []Expression
+	Rest     *Identifier // Binds the remaining elements, allowing longer arrays
+}
+
+func (pattern *ArrayPattern) expressionNode()       {}
+func (pattern *ArrayPattern) TokenLiteral() string  { return pattern.Token.Literal }
+func (pattern *ArrayPattern) GetToken() token.Token { return pattern.Token }
+func (pattern *ArrayPattern) String() string {
+	elements := []string{}
+	for _, element := range pattern.Elements {
+		elements = append(elements, element.String())
+	}
+
+	if pattern.Rest != nil {
+		elements = append(elements, "..."+pattern.Rest.String())
+	}
+
+	return "[" + strings.Join(elements, ", ") + "]"
+}
+
+// Hash pattern, matching hashes containing at least the given keys
+type HashPattern struct {
+	Token token.Token // LBRACE '{' token
+	Pairs []*HashPatternPair
+	Rest  *Identifier // Binds a hash of the remaining pairs
+}
+
+type HashPatternPair struct {
+	Key   Expression
+	Value Expression
+}
+
+func (pattern *HashPattern) expressionNode()       {}
+func (pattern *HashPattern) TokenLiteral() string  { return pattern.Token.Literal }
+func (pattern *HashPattern) GetToken() token.Token { return pattern.Token }
+func (pattern *HashPattern) String() string {
+	pairs := []string{}
+	for _, pair := range pattern.Pairs {
+		pairs = append(pairs, pair.Key.String()+":"+pair.Value.String())
+	}
+
+	if pattern.Rest != nil {
+		pairs = append(pairs, "..."+pattern.Rest.String())
+	}
+
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
@@ -0,0 +1,167 @@
+package diagnostic
+
+import (
+	"encoding/json"
+	"fmt"
+	"glass/language/object"
+	"glass/language/token"
+	"unicode/utf8"
+)
+
+type Severity string
+
+const (
+	ERROR   Severity = "error"
+	WARNING Severity = "warning"
+)
+
+// Syntax error codes
+const (
+	UNEXPECTED_TOKEN   = "E0001"
+	ILLEGAL_TOKEN      = "E0002"
+	INVALID_LITERAL    = "E0003"
+	INVALID_PATTERN    = "E0004"
+	INVALID_ASSIGNMENT = "E0005"
+	MISSING_EXPRESSION = "E0006"
+	INVALID_STATEMENT  = "E0007"
+	INVALID_ARGUMENT   = "E0008"
+)
+
+// Runtime error codes, one per error kind
+const (
+	RUNTIME_ERROR    = "E1000"
+	TYPE_ERROR       = "E1001"
+	REFERENCE_ERROR  = "E1002"
+	RANGE_ERROR      = "E1003"
+	VALUE_ERROR      = "E1004"
+	ARITHMETIC_ERROR = "E1005"
+	ARGUMENT_ERROR   = "E1006"
+	SYNTAX_ERROR     = "E1007"
+)
+
+// Error code for source files that cannot be read
+const FILE_ERROR = "E2000"
+
+var runtimeErrorCodes = map[string]string{
+	object.TYPE_ERROR:       TYPE_ERROR,
+	object.REFERENCE_ERROR:  REFERENCE_ERROR,
+	object.RANGE_ERROR:      RANGE_ERROR,
+	object.VALUE_ERROR:      VALUE_ERROR,
+	object.ARITHMETIC_ERROR: ARITHMETIC_ERROR,
+	object.ARGUMENT_ERROR:   ARGUMENT_ERROR,
+	object.SYNTAX_ERROR:     SYNTAX_ERROR,
+}
+
+// Span of source code, with a 1-based line and a 0-based position like tokens
+type Span struct {
+	Line     int
+	Position int
+	Length   int
+}
+
+type Diagnostic struct {
+	Severity Severity
+	Code     string
+	Message  string
+	File     string
+	Span     Span
+	Notes    []string
+}
+
+func New(code string, message string, file string, span Span) *Diagnostic {
+	return &Diagnostic{
+		Severity: ERROR,
+		Code:     code,
+		Message:  message,
+		File:     file,
+		Span:     span,
+	}
+}
+
+// Span covering a whole token, at least one character long
+func TokenSpan(spanned token.Token) Span {
+	length := spanned.Length
+	if length == 0 {
+		length = utf8.RuneCountInString(spanned.Literal)
+	}
+
+	if length == 0 {
+		length = 1
+	}
+
+	return Span{
+		Line:     spanned.Line,
+		Position: spanned.Position,
+		Length:   length,
+	}
+}
+
+// Diagnostic for an uncaught runtime error, its call stack becoming notes
+func FromError(err *object.Error) *Diagnostic {
+	code, ok := runtimeErrorCodes[err.Kind]
+	if !ok {
+		code = RUNTIME_ERROR
+	}
+
+	diagnostic := New(code, err.Kind+": "+err.Message, err.File, Span{
+		Line:     err.Line,
+		Position: err.Position,
+		Length:   1,
+	})
+
+	for _, frame := range err.Stack {
+		diagnostic.Notes = append(diagnostic.Notes, fmt.Sprintf(
+			"in %s, called from %s:%d:%d",
+			frame.Function,
+			frame.File,
+			frame.Line,
+			frame.Position+1,
+		))
+	}
+
+	return diagnostic
+}
+
+// Diagnostic for a source file that could not be read, pointing at no line
+func FromFileError(file string, err error) *Diagnostic {
+	return New(FILE_ERROR, "Could not read file: "+err.Error(), file, Span{})
+}
+
+// Legacy single line format, as returned by the parser's GetErrors
+func (diagnostic *Diagnostic) String() string {
+	return fmt.Sprintf("%s (l.%d:p.%d)", diagnostic.Message, diagnostic.Span.Line, diagnostic.Span.Position)
+}
+
+// JSON object with a 1-based column, for tools consuming diagnostics
+func (diagnostic *Diagnostic) MarshalJSON() ([]byte, error) {
+	notes := diagnostic.Notes
+	if notes == nil {
+		notes = []string{}
+	}
+
+	// Diagnostics about a whole file have neither a line nor a column
+	column := diagnostic.Span.Position + 1
+	if diagnostic.Span.Line == 0 {
+		column = 0
+	}
+
+	return json.Marshal(struct {
+		Severity Severity `json:"severity"`
+		Code     string   `json:"code"`
+		Message  string   `json:"message"`
+		File     string   `json:"file"`
+		Line     int      `json:"line"`
+		Column   int      `json:"column"`
+		Length   int      `json:"length"`
+		Notes    []string `json:"notes"`
+	}{
+		Severity: diagnostic.Severity,
+		Code:     diagnostic.Code,
+		Message:  diagnostic.Message,
+		File:     diagnostic.File,
+		Line:     diagnostic.Span.Line,
+		Column:   column,
+		Length:   diagnostic.Span.Length,
+		Notes:    notes,
+	})
+}
@@ -0,0 +1,142 @@
+package diagnostic
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	RESET  = "\033[0m"
+	BOLD   = "\033[1m"
+	RED    = "\033[1;31m"
+	YELLOW = "\033[1;33m"
+	BLUE   = "\033[1;34m"
+)
+
+type Renderer struct {
+	Color   bool
+	sources map[string][]string
+}
+
+func NewRenderer(color bool) *Renderer {
+	return &Renderer{
+		Color:   color,
+		sources: make(map[string][]string),
+	}
+}
+
+// Colors are only used on terminals, and can be disabled with NO_COLOR
+func IsColorTerminal(file *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	info, statError := file.Stat()
+	if statError != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// Registers the source of a file, files not registered are read from disk
+func (renderer *Renderer) AddSource(file string, source string) {
+	renderer.sources[file] = strings.Split(source, "\n")
+}
+
+func (renderer *Renderer) Render(diagnostic *Diagnostic) string {
+	var buffer bytes.Buffer
+
+	severityColor := RED
+	if diagnostic.Severity == WARNING {
+		severityColor = YELLOW
+	}
+
+	// Header
+	buffer.WriteString(renderer.paint(severityColor, string(diagnostic.Severity)))
+	if diagnostic.Code != "" {
+		buffer.WriteString(renderer.paint(severityColor, "["+diagnostic.Code+"]"))
+	}
+	buffer.WriteString(renderer.paint(BOLD, ": "+diagnostic.Message))
+	buffer.WriteString("\n")
+
+	span := diagnostic.Span
+	lineNumber := strconv.Itoa(span.Line)
+	gutter := strings.Repeat(" ", len(lineNumber))
+
+	// Location
+	buffer.WriteString(gutter + renderer.paint(BLUE, "--> "))
+	if span.Line == 0 {
+		buffer.WriteString(diagnostic.File + "\n")
+	} else {
+		buffer.WriteString(fmt.Sprintf("%s:%d:%d\n", diagnostic.File, span.Line, span.Position+1))
+	}
+
+	// Snippet, underlining the span
+	sourceLine, ok := renderer.getSourceLine(diagnostic.File, span.Line)
+	if ok {
+		buffer.WriteString(gutter + renderer.paint(BLUE, " |") + "\n")
+		buffer.WriteString(renderer.paint(BLUE, lineNumber+" |") + " " + sourceLine + "\n")
+		buffer.WriteString(gutter + renderer.paint(BLUE, " |") + " ")
+		buffer.WriteString(renderer.paint(severityColor, underline(sourceLine, span)))
+		buffer.WriteString("\n")
+	}
+
+	// Notes
+	for _, note := range diagnostic.Notes {
+		buffer.WriteString(gutter + renderer.paint(BLUE, " = ") + renderer.paint(BOLD, "note") + ": " + note + "\n")
+	}
+
+	return buffer.String()
+}
+
+func (renderer *Renderer) getSourceLine(file string, line int) (string, bool) {
+	lines, ok := renderer.sources[file]
+	if !ok {
+		content, readError := os.ReadFile(file)
+		if readError != nil {
+			return "", false
+		}
+
+		renderer.AddSource(file, string(content))
+		lines = renderer.sources[file]
+	}
+
+	if line < 1 || line > len(lines) {
+		return "", false
+	}
+
+	return strings.TrimRight(lines[line-1], "\r"), true
+}
+
+func (renderer *Renderer) paint(color string, text string) string {
+	if !renderer.Color {
+		return text
+	}
+
+	return color + text + RESET
+}
+
+// Caret under the start of the span followed by tildes, keeping tabs aligned
+func underline(sourceLine string, span Span) string {
+	var buffer bytes.Buffer
+
+	runes := []rune(sourceLine)
+	for i := 0; i < span.Position; i++ {
+		if i < len(runes) && runes[i] == '\t' {
+			buffer.WriteRune('\t')
+		} else {
+			buffer.WriteRune(' ')
+		}
+	}
+
+	buffer.WriteString("^")
+	if span.Length > 1 {
+		buffer.WriteString(strings.Repeat("~", span.Length-1))
+	}
+
+	return buffer.String()
+}
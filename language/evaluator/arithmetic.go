@@ -0,0 +1,55 @@
+package evaluator
+
+import (
+	"glass/language/object"
+	"math"
+)
+
+// Integer operations wrap around on overflow, unless the program runs with checked arithmetic
+
+func isAdditionOverflowing(left int64, right int64) bool {
+	result := left + right
+	return (left > 0 && right > 0 && result < 0) || (left < 0 && right < 0 && result >= 0)
+}
+
+func isSubtractionOverflowing(left int64, right int64) bool {
+	result := left - right
+	return (left >= 0 && right < 0 && result < 0) || (left < 0 && right > 0 && result >= 0)
+}
+
+func isMultiplicationOverflowing(left int64, right int64) bool {
+	if left == 0 || right == 0 {
+		return false
+	}
+
+	if (left == -1 && right == math.MinInt64) || (right == -1 && left == math.MinInt64) {
+		return true
+	}
+
+	return (left*right)/right != left
+}
+
+// Exponentiation by squaring, also reporting whether the result overflowed
+func integerPower(base int64, exponent int64) (int64, bool) {
+	result := int64(1)
+	isOverflowing := false
+
+	for exponent > 0 {
+		if exponent%2 == 1 {
+			isOverflowing = isOverflowing || isMultiplicationOverflowing(result, base)
+			result *= base
+		}
+
+		exponent /= 2
+		if exponent > 0 {
+			isOverflowing = isOverflowing || isMultiplicationOverflowing(base, base)
+			base *= base
+		}
+	}
+
+	return result, isOverflowing
+}
+
+func newOverflowError(operator string, left int64, right int64) *object.Error {
+	return newErrorOfKind(object.ARITHMETIC_ERROR, "integer overflow: %d %s %d", left, operator, right)
+}
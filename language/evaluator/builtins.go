@@ -3,6 +3,9 @@ package evaluator
 import (
 	"fmt"
 	"glass/language/object"
+	"math"
+	"strconv"
+	"strings"
 )
 
 var builtins = map[string]*object.Builtin{
@@ -17,4 +20,89 @@ var builtins = map[string]*object.Builtin{
 			return NULL
 		},
 	},
+	"int": {
+		Function: func(arguments ...object.Object) object.Object {
+			if len(arguments) != 1 {
+				return newErrorOfKind(object.ARGUMENT_ERROR, "wrong number of arguments. got=%d, want=1", len(arguments))
+			}
+
+			switch argument := arguments[0].(type) {
+
+			case *object.Integer:
+				return argument
+
+			case *object.Float:
+				if math.IsNaN(argument.Value) || argument.Value < math.MinInt64 || argument.Value >= math.MaxInt64 {
+					return newErrorOfKind(object.VALUE_ERROR, "could not convert %s to INTEGER", argument.Inspect())
+				}
+
+				return &object.Integer{Value: int64(argument.Value)}
+
+			case *object.String:
+				value, conversionError := strconv.ParseInt(strings.TrimSpace(argument.Value), 10, 64)
+				if conversionError != nil {
+					return newErrorOfKind(object.VALUE_ERROR, "could not convert %q to INTEGER", argument.Value)
+				}
+
+				return &object.Integer{Value: value}
+
+			default:
+				return newErrorOfKind(object.TYPE_ERROR, "argument to `int` not supported, got %s", argument.GetType())
+
+			}
+		},
+	},
+	"float": {
+		Function: func(arguments ...object.Object) object.Object {
+			if len(arguments) != 1 {
+				return newErrorOfKind(object.ARGUMENT_ERROR, "wrong number of arguments. got=%d, want=1", len(arguments))
+			}
+
+			switch argument := arguments[0].(type) {
+
+			case *object.Integer:
+				return &object.Float{Value: float64(argument.Value)}
+
+			case *object.Float:
+				return argument
+
+			case *object.String:
+				value, conversionError := strconv.ParseFloat(strings.TrimSpace(argument.Value), 64)
+				if conversionError != nil {
+					return newErrorOfKind(object.VALUE_ERROR, "could not convert %q to FLOAT", argument.Value)
+				}
+
+				return &object.Float{Value: value}
+
+			default:
+				return newErrorOfKind(object.TYPE_ERROR, "argument to `float` not supported, got %s", argument.GetType())
+
+			}
+		},
+	},
+	// Creates an exception to throw, as in error("message") or error("message", "KindError")
+	"error": {
+		Function: func(arguments ...object.Object) object.Object {
+			if len(arguments) != 1 && len(arguments) != 2 {
+				return newErrorOfKind(object.ARGUMENT_ERROR, "wrong number of arguments. got=%d, want=1 or 2", len(arguments))
+			}
+
+			err := &object.Error{Kind: object.GENERIC_ERROR}
+
+			for index, argument := range arguments {
+				value, ok := argument.(*object.String)
+				if !ok {
+					return newErrorOfKind(object.TYPE_ERROR, "arguments to `error` must be STRING, got %s", argument.GetType())
+				}
+
+				if index == 0 {
+					err.Message = value.Value
+				} else {
+					err.Kind = value.Value
+				}
+			}
+
+			return &object.Exception{Error: err}
+		},
+	},
 }
@@ -5,18 +5,50 @@ import (
 	"glass/language/ast"
 	"glass/language/object"
 	"glass/language/parser"
-	"log"
+	"math"
 	"path"
 	"path/filepath"
+	"sort"
+	"strings"
 )
 
 var (
 	NULL  = &object.Null{}
 	TRUE  = &object.Boolean{Value: true}
 	FALSE = &object.Boolean{Value: false}
+
+	SKIPPED_CHAIN = &object.SkippedChain{}
 )
 
 func Evaluate(node ast.Node, environment *object.Environment) object.Object {
+	result := evaluateChainLink(node, environment)
+
+	// Chains skipped by an optional link end up null
+	if isSkippedChain(result) {
+		return NULL
+	}
+
+	return result
+}
+
+// Evaluates a node without ending the chain it belongs to, so that the links
+// after it can see it was skipped
+func evaluateChainLink(node ast.Node, environment *object.Environment) object.Object {
+	result := evaluateNode(node, environment)
+
+	// Errors point to the innermost node they were raised from
+	if err, ok := result.(*object.Error); ok && err.Line == 0 {
+		if nodeToken := node.GetToken(); nodeToken.Line > 0 {
+			err.File = environment.Filepath
+			err.Line = nodeToken.Line
+			err.Position = nodeToken.Position
+		}
+	}
+
+	return result
+}
+
+func evaluateNode(node ast.Node, environment *object.Environment) object.Object {
 	switch node := node.(type) {
 
 	// Statements
@@ -25,51 +57,93 @@ func Evaluate(node ast.Node, environment *object.Environment) object.Object {
 
 	case *ast.LetStatement:
 		value := Evaluate(node.Expression, environment)
-		if isError(value) {
+		if isInterrupting(value) {
 			return value
 		}
 
-		environment.Set(node.Identifier.Value, value)
+		// Naming anonymous functions after their variable for tracebacks
+		if function, ok := value.(*object.Function); ok && function.Name == "" {
+			if identifier, ok := node.Pattern.(*ast.Identifier); ok {
+				function.Name = identifier.Value
+			}
+		}
+
+		err := bindPattern(node.Pattern, value, environment)
+		if err != nil {
+			return err
+		}
 
 	case *ast.ExpressionStatement:
 		return Evaluate(node.Expression, environment)
 
 	case *ast.ReturnStatement:
 		value := Evaluate(node.Expression, environment)
-		if isError(value) {
+		if isInterrupting(value) {
 			return value
 		}
 
 		return &object.ReturnValue{
-			Value: Evaluate(node.Expression, environment),
+			Value: value,
 		}
 
+	case *ast.ThrowStatement:
+		return evaluateThrowStatement(node, environment)
+
+	case *ast.TryStatement:
+		return evaluateTryStatement(node, environment)
+
 	case *ast.ImportStatement:
-		evaluateImportStatement(node, environment)
+		return evaluateImportStatement(node, environment)
 
 	case *ast.ExportStatement:
 		evaluateExportStatement(node, environment)
 
+	case *ast.WhileStatement:
+		return evaluateWhileStatement(node, environment)
+
+	case *ast.ForStatement:
+		return evaluateForStatement(node, environment)
+
+	case *ast.ForInStatement:
+		return evaluateForInStatement(node, environment)
+
+	case *ast.BreakStatement:
+		return &object.Break{Label: getLoopLabel(node.Label)}
+
+	case *ast.ContinueStatement:
+		return &object.Continue{Label: getLoopLabel(node.Label)}
+
 	// Expressions
 	case *ast.IntegerLiteral:
 		return &object.Integer{
 			Value: node.Value,
 		}
 
+	case *ast.FloatLiteral:
+		return &object.Float{
+			Value: node.Value,
+		}
+
 	case *ast.StringLiteral:
 		return &object.String{
 			Value: node.Value,
 		}
 
+	case *ast.TemplateLiteral:
+		return evaluateTemplateLiteral(node, environment)
+
 	case *ast.Boolean:
 		return newBooleanObject(node.Value)
 
+	case *ast.NullLiteral:
+		return NULL
+
 	case *ast.Identifier:
 		return evaluateIdentifier(node, environment)
 
 	case *ast.ArrayLiteral:
 		elements := evaluateExpressions(node.Elements, environment)
-		if len(elements) == 1 && isError(elements[0]) {
+		if len(elements) == 1 && isInterrupting(elements[0]) {
 			return elements[0]
 		}
 
@@ -80,23 +154,29 @@ func Evaluate(node ast.Node, environment *object.Environment) object.Object {
 
 	case *ast.PrefixExpression:
 		right := Evaluate(node.Expression, environment)
-		if isError(right) {
+		if isInterrupting(right) {
 			return right
 		}
 		return evaluatePrefixExpression(node.Operator, right)
 
 	case *ast.InfixExpression:
 		left := Evaluate(node.LeftExpression, environment)
-		if isError(left) {
+		if isInterrupting(left) {
 			return left
 		}
 
 		right := Evaluate(node.RightExpression, environment)
-		if isError(right) {
+		if isInterrupting(right) {
 			return right
 		}
 
-		return evaluateInfixExpression(node.Operator, left, right)
+		return evaluateInfixExpression(node.Operator, left, right, environment)
+
+	case *ast.LogicalExpression:
+		return evaluateLogicalExpression(node, environment)
+
+	case *ast.AssignmentExpression:
+		return evaluateAssignmentExpression(node, environment)
 
 	case *ast.BlockStatement:
 		return evaluateBlockStatement(node, environment)
@@ -104,14 +184,24 @@ func Evaluate(node ast.Node, environment *object.Environment) object.Object {
 	case *ast.IfExpression:
 		return evaluateIfExpression(node, environment)
 
+	case *ast.ConditionalExpression:
+		return evaluateConditionalExpression(node, environment)
+
+	case *ast.MatchExpression:
+		return evaluateMatchExpression(node, environment)
+
 	case *ast.IndexExpression:
-		left := Evaluate(node.Left, environment)
-		if isError(left) {
+		left := evaluateChainLink(node.Left, environment)
+		if isInterrupting(left) || isSkippedChain(left) {
 			return left
 		}
 
+		if node.Optional && left == NULL {
+			return SKIPPED_CHAIN
+		}
+
 		index := Evaluate(node.Index, environment)
-		if isError(index) {
+		if isInterrupting(index) {
 			return index
 		}
 
@@ -130,17 +220,20 @@ func Evaluate(node ast.Node, environment *object.Environment) object.Object {
 		}
 
 	case *ast.CallExpression:
-		function := Evaluate(node.Function, environment)
-		if isError(function) {
+		function := evaluateChainLink(node.Function, environment)
+		if isInterrupting(function) || isSkippedChain(function) {
 			return function
 		}
 
-		arguments := evaluateExpressions(node.Arguments, environment)
-		if len(arguments) == 1 && isError(arguments[0]) {
-			return arguments[0]
+		arguments, namedArguments, err := evaluateArguments(node.Arguments, environment)
+		if err != nil {
+			return err
 		}
 
-		return applyFunction(function, arguments)
+		return applyFunction(function, arguments, namedArguments, node, environment)
+
+	case *ast.SpreadExpression:
+		return newErrorOfKind(object.TYPE_ERROR, "cannot spread %s outside of a call or an array", node.Expression.String())
 
 	}
 
@@ -175,7 +268,10 @@ func evaluateBlockStatement(blockStatement *ast.BlockStatement, environment *obj
 
 		if result != nil {
 			resultType := result.GetType()
-			if resultType == object.RETURN_VALUE_OBJECT || resultType == object.ERROR_OBJECT {
+			if resultType == object.RETURN_VALUE_OBJECT ||
+				resultType == object.ERROR_OBJECT ||
+				resultType == object.BREAK_OBJECT ||
+				resultType == object.CONTINUE_OBJECT {
 				return result
 			}
 		}
@@ -184,20 +280,220 @@ func evaluateBlockStatement(blockStatement *ast.BlockStatement, environment *obj
 	return result
 }
 
+func evaluateThrowStatement(statement *ast.ThrowStatement, environment *object.Environment) object.Object {
+	value := Evaluate(statement.Expression, environment)
+	if isInterrupting(value) {
+		return value
+	}
+
+	switch value := value.(type) {
+
+	// Copying the error keeps the original one untouched when rethrowing
+	case *object.Exception:
+		err := *value.Error
+		err.Stack = append([]object.StackFrame{}, value.Error.Stack...)
+		return &err
+
+	case *object.String:
+		return &object.Error{Kind: object.GENERIC_ERROR, Message: value.Value, Value: value}
+
+	default:
+		return &object.Error{Kind: object.GENERIC_ERROR, Message: value.Inspect(), Value: value}
+
+	}
+}
+
+func evaluateTryStatement(statement *ast.TryStatement, environment *object.Environment) object.Object {
+	result := evaluateBlockStatement(statement.Body, environment)
+
+	if err, ok := result.(*object.Error); ok && statement.Catch != nil {
+		catchEnvironment := object.NewEnclosedEnvironment(environment)
+		if statement.CatchParameter != nil {
+			catchEnvironment.Set(statement.CatchParameter.Value, &object.Exception{Error: err})
+		}
+
+		result = evaluateBlockStatement(statement.Catch, catchEnvironment)
+	}
+
+	if statement.Finally == nil {
+		return result
+	}
+
+	// Returning, breaking or failing from a finally block replaces the previous result
+	finallyResult := evaluateBlockStatement(statement.Finally, environment)
+	if finallyResult != nil {
+		switch finallyResult.GetType() {
+
+		case object.RETURN_VALUE_OBJECT, object.ERROR_OBJECT, object.BREAK_OBJECT, object.CONTINUE_OBJECT:
+			return finallyResult
+
+		}
+	}
+
+	return result
+}
+
+func evaluateWhileStatement(statement *ast.WhileStatement, environment *object.Environment) object.Object {
+	for {
+		condition := Evaluate(statement.Condition, environment)
+		if isInterrupting(condition) {
+			return condition
+		}
+
+		if !isTruthy(condition) {
+			return NULL
+		}
+
+		result, ok := evaluateLoopBody(statement.Body, statement.Label, environment)
+		if !ok {
+			return result
+		}
+	}
+}
+
+func evaluateForStatement(statement *ast.ForStatement, environment *object.Environment) object.Object {
+	loopEnvironment := object.NewEnclosedEnvironment(environment)
+
+	if statement.Initialization != nil {
+		initialization := Evaluate(statement.Initialization, loopEnvironment)
+		if isInterrupting(initialization) {
+			return initialization
+		}
+	}
+
+	for {
+		if statement.Condition != nil {
+			condition := Evaluate(statement.Condition, loopEnvironment)
+			if isInterrupting(condition) {
+				return condition
+			}
+
+			if !isTruthy(condition) {
+				return NULL
+			}
+		}
+
+		result, ok := evaluateLoopBody(statement.Body, statement.Label, loopEnvironment)
+		if !ok {
+			return result
+		}
+
+		if statement.Update != nil {
+			update := Evaluate(statement.Update, loopEnvironment)
+			if isInterrupting(update) {
+				return update
+			}
+		}
+	}
+}
+
+func evaluateForInStatement(statement *ast.ForInStatement, environment *object.Environment) object.Object {
+	iterable := Evaluate(statement.Iterable, environment)
+	if isInterrupting(iterable) {
+		return iterable
+	}
+
+	var elements []object.Object
+
+	switch iterable := iterable.(type) {
+
+	case *object.Array:
+		elements = iterable.Elements
+
+	case *object.String:
+		for _, character := range iterable.Value {
+			elements = append(elements, &object.String{Value: string(character)})
+		}
+
+	case *object.Hash:
+		elements = iterable.SortedKeys()
+
+	default:
+		return newErrorOfKind(object.TYPE_ERROR, "cannot iterate over %s", iterable.GetType())
+
+	}
+
+	for _, element := range elements {
+		// Each iteration has its own environment, for closures to keep their element
+		loopEnvironment := object.NewEnclosedEnvironment(environment)
+		loopEnvironment.Set(statement.Identifier.Value, element)
+
+		result, ok := evaluateLoopBody(statement.Body, statement.Label, loopEnvironment)
+		if !ok {
+			return result
+		}
+	}
+
+	return NULL
+}
+
+// Returns false when the loop has to stop, along with the result to pass up
+func evaluateLoopBody(
+	body *ast.BlockStatement,
+	label *ast.Identifier,
+	environment *object.Environment,
+) (object.Object, bool) {
+	result := evaluateBlockStatement(body, environment)
+
+	switch result := result.(type) {
+
+	case *object.ReturnValue, *object.Error:
+		return result, false
+
+	case *object.Break:
+		if result.Label == "" || result.Label == getLoopLabel(label) {
+			return NULL, false
+		}
+
+		return result, false
+
+	case *object.Continue:
+		if result.Label == "" || result.Label == getLoopLabel(label) {
+			return nil, true
+		}
+
+		return result, false
+
+	}
+
+	return nil, true
+}
+
+func getLoopLabel(label *ast.Identifier) string {
+	if label == nil {
+		return ""
+	}
+
+	return label.Value
+}
+
 func evaluateImportStatement(importStatement *ast.ImportStatement, environment *object.Environment) object.Object {
 
 	filePath := path.Join(filepath.Dir(environment.Filepath), filepath.Clean(importStatement.Path))
 
-	program := parser.GetParsedFile(filePath)
+	program, diagnostics, readError := parser.GetParsedFile(filePath)
+	if readError != nil {
+		return newErrorOfKind(object.REFERENCE_ERROR, "cannot import %s: %s", importStatement.Path, readError)
+	}
+
+	// Reporting the first syntax error of the module at its own location
+	if len(diagnostics) > 0 {
+		return &object.Error{
+			Kind:     object.SYNTAX_ERROR,
+			Message:  diagnostics[0].Message,
+			File:     diagnostics[0].File,
+			Line:     diagnostics[0].Span.Line,
+			Position: diagnostics[0].Span.Position,
+		}
+	}
 
 	if !environment.ProgramEnvironment.IsModuleEvaluated(filePath) {
 		moduleEnvironment := object.NewEnvironment(filePath, environment.ProgramEnvironment)
 		moduleEnvironment.ProgramEnvironment.RegisterModule(filePath)
 
 		result := Evaluate(program, moduleEnvironment)
-
-		if result != nil && result.GetType() == object.ERROR_OBJECT {
-			log.Fatal(result.Inspect())
+		if isError(result) {
+			return result
 		}
 
 	}
@@ -234,14 +530,24 @@ func evaluateIdentifier(
 		return builtin
 	}
 
-	return newError("identifier not found: " + identifier.Value)
+	return newErrorOfKind(object.REFERENCE_ERROR, "identifier not found: "+identifier.Value)
 }
 
 func evaluateExpressions(expressions []ast.Expression, environment *object.Environment) []object.Object {
 	var result []object.Object
 	for _, expression := range expressions {
+		if spread, ok := expression.(*ast.SpreadExpression); ok {
+			elements, err := evaluateSpreadExpression(spread, environment)
+			if err != nil {
+				return []object.Object{err}
+			}
+
+			result = append(result, elements...)
+			continue
+		}
+
 		evaluated := Evaluate(expression, environment)
-		if isError(evaluated) {
+		if isInterrupting(evaluated) {
 			return []object.Object{evaluated}
 		}
 
@@ -251,6 +557,87 @@ func evaluateExpressions(expressions []ast.Expression, environment *object.Envir
 	return result
 }
 
+func evaluateSpreadExpression(
+	expression *ast.SpreadExpression,
+	environment *object.Environment,
+) ([]object.Object, object.Object) {
+	evaluated := Evaluate(expression.Expression, environment)
+	if isInterrupting(evaluated) {
+		return nil, evaluated
+	}
+
+	array, ok := evaluated.(*object.Array)
+	if !ok {
+		return nil, newErrorOfKind(object.TYPE_ERROR, "cannot spread %s, expected ARRAY", evaluated.GetType())
+	}
+
+	return array.Elements, nil
+}
+
+// Splits call arguments between positional ones, with spreads expanded, and named ones
+func evaluateArguments(
+	expressions []ast.Expression,
+	environment *object.Environment,
+) ([]object.Object, map[string]object.Object, object.Object) {
+	positionalExpressions := []ast.Expression{}
+	namedArguments := make(map[string]object.Object)
+
+	for _, expression := range expressions {
+		named, ok := expression.(*ast.NamedArgument)
+		if !ok {
+			positionalExpressions = append(positionalExpressions, expression)
+			continue
+		}
+
+		if _, exists := namedArguments[named.Name.Value]; exists {
+			return nil, nil, newErrorOfKind(object.ARGUMENT_ERROR, "multiple values for argument %s", named.Name.Value)
+		}
+
+		value := Evaluate(named.Value, environment)
+		if isInterrupting(value) {
+			return nil, nil, value
+		}
+
+		namedArguments[named.Name.Value] = value
+	}
+
+	arguments := evaluateExpressions(positionalExpressions, environment)
+	if len(arguments) == 1 && isInterrupting(arguments[0]) {
+		return nil, nil, arguments[0]
+	}
+
+	return arguments, namedArguments, nil
+}
+
+func evaluateTemplateLiteral(template *ast.TemplateLiteral, environment *object.Environment) object.Object {
+	var builder strings.Builder
+
+	for _, part := range template.Parts {
+		value := evaluateTemplatePart(part, environment)
+		if isInterrupting(value) {
+			return value
+		}
+
+		builder.WriteString(value.Inspect())
+	}
+
+	return &object.String{Value: builder.String()}
+}
+
+func evaluateTemplatePart(part ast.Expression, environment *object.Environment) object.Object {
+	interpolation, ok := part.(*ast.Interpolation)
+	if !ok {
+		return Evaluate(part, environment)
+	}
+
+	value := Evaluate(interpolation.Expression, environment)
+	if value == nil {
+		return NULL
+	}
+
+	return value
+}
+
 func evaluatePrefixExpression(operator string, right object.Object) object.Object {
 	switch operator {
 
@@ -260,8 +647,11 @@ func evaluatePrefixExpression(operator string, right object.Object) object.Objec
 	case "-":
 		return evaluateMinusPrefixExpression(right)
 
+	case "~":
+		return evaluateBitwiseNotExpression(right)
+
 	default:
-		return newError("unknown operator: %s%s", operator, right.GetType())
+		return newErrorOfKind(object.TYPE_ERROR, "unknown operator: %s%s", operator, right.GetType())
 
 	}
 }
@@ -285,21 +675,46 @@ func evaluateNotOperatorExpression(right object.Object) object.Object {
 }
 
 func evaluateMinusPrefixExpression(right object.Object) object.Object {
-	if right.GetType() != object.INTEGER_OBJECT {
-		return newError("unknown operator: -%s", right.GetType())
+	switch right := right.(type) {
+
+	case *object.Integer:
+		return &object.Integer{Value: -right.Value}
+
+	case *object.Float:
+		return &object.Float{Value: -right.Value}
+
+	default:
+		return newErrorOfKind(object.TYPE_ERROR, "unknown operator: -%s", right.GetType())
+
 	}
+}
 
-	value := right.(*object.Integer).Value
-	return &object.Integer{
-		Value: -value,
+func evaluateBitwiseNotExpression(right object.Object) object.Object {
+	integer, ok := right.(*object.Integer)
+	if !ok {
+		return newErrorOfKind(object.TYPE_ERROR, "bitwise operator ~ requires an INTEGER operand, got %s", right.GetType())
 	}
+
+	return &object.Integer{Value: ^integer.Value}
 }
 
-func evaluateInfixExpression(operator string, left object.Object, right object.Object) object.Object {
+func evaluateInfixExpression(
+	operator string,
+	left object.Object,
+	right object.Object,
+	environment *object.Environment,
+) object.Object {
 	switch {
 
+	case isBitwiseOperator(operator):
+		return evaluateBitwiseInfixExpression(operator, left, right)
+
 	case left.GetType() == object.INTEGER_OBJECT && right.GetType() == object.INTEGER_OBJECT:
-		return evaluateIntegerInfixExpression(operator, left, right)
+		return evaluateIntegerInfixExpression(operator, left, right, environment)
+
+	// Integers are promoted when mixed with floats
+	case isNumber(left) && isNumber(right):
+		return evaluateFloatInfixExpression(operator, left, right)
 
 	case left.GetType() == object.STRING_OBJECT && right.GetType() == object.STRING_OBJECT:
 		return evaluateStringInfixExpression(operator, left, right)
@@ -311,38 +726,86 @@ func evaluateInfixExpression(operator string, left object.Object, right object.O
 		return newBooleanObject(left != right)
 
 	case left.GetType() != right.GetType():
-		return newError("type mismatch: %s %s %s", left.GetType(), operator, right.GetType())
+		return newErrorOfKind(object.TYPE_ERROR, "type mismatch: %s %s %s", left.GetType(), operator, right.GetType())
 
 	default:
-		return newError("unknown operator: %s %s %s", left.GetType(), operator, right.GetType())
+		return newErrorOfKind(object.TYPE_ERROR, "unknown operator: %s %s %s", left.GetType(), operator, right.GetType())
 
 	}
 }
 
-func evaluateIntegerInfixExpression(operator string, left object.Object, right object.Object) object.Object {
+func evaluateIntegerInfixExpression(
+	operator string,
+	left object.Object,
+	right object.Object,
+	environment *object.Environment,
+) object.Object {
 	leftValue := left.(*object.Integer).Value
 	rightValue := right.(*object.Integer).Value
+	isChecked := environment.ProgramEnvironment.CheckedArithmetic
 
 	switch operator {
 
 	case "+":
+		if isChecked && isAdditionOverflowing(leftValue, rightValue) {
+			return newOverflowError(operator, leftValue, rightValue)
+		}
+
 		return &object.Integer{Value: leftValue + rightValue}
 
 	case "-":
+		if isChecked && isSubtractionOverflowing(leftValue, rightValue) {
+			return newOverflowError(operator, leftValue, rightValue)
+		}
+
 		return &object.Integer{Value: leftValue - rightValue}
 
 	case "*":
+		if isChecked && isMultiplicationOverflowing(leftValue, rightValue) {
+			return newOverflowError(operator, leftValue, rightValue)
+		}
+
 		return &object.Integer{Value: leftValue * rightValue}
 
 	case "/":
+		if rightValue == 0 {
+			return newErrorOfKind(object.ARITHMETIC_ERROR, "division by zero: %d / 0", leftValue)
+		}
+
 		return &object.Integer{Value: leftValue / rightValue}
 
+	case "%":
+		if rightValue == 0 {
+			return newErrorOfKind(object.ARITHMETIC_ERROR, "modulo by zero: %d %% 0", leftValue)
+		}
+
+		return &object.Integer{Value: leftValue % rightValue}
+
+	case "**":
+		// Negative exponents cannot produce integers
+		if rightValue < 0 {
+			return &object.Float{Value: math.Pow(float64(leftValue), float64(rightValue))}
+		}
+
+		result, isOverflowing := integerPower(leftValue, rightValue)
+		if isChecked && isOverflowing {
+			return newOverflowError(operator, leftValue, rightValue)
+		}
+
+		return &object.Integer{Value: result}
+
 	case "<":
 		return newBooleanObject(leftValue < rightValue)
 
 	case ">":
 		return newBooleanObject(leftValue > rightValue)
 
+	case "<=":
+		return newBooleanObject(leftValue <= rightValue)
+
+	case ">=":
+		return newBooleanObject(leftValue >= rightValue)
+
 	case "==":
 		return newBooleanObject(leftValue == rightValue)
 
@@ -350,82 +813,543 @@ func evaluateIntegerInfixExpression(operator string, left object.Object, right o
 		return newBooleanObject(leftValue != rightValue)
 
 	default:
-		return newError("unknown operator: %s %s %s", left.GetType(), operator, right.GetType())
+		return newErrorOfKind(object.TYPE_ERROR, "unknown operator: %s %s %s", left.GetType(), operator, right.GetType())
 
 	}
 }
 
-func evaluateStringInfixExpression(operator string, left object.Object, right object.Object) object.Object {
-	if operator != "+" {
-		return newError("unknown operator: %s %s %s", left.GetType(), operator, right.GetType())
+func evaluateBitwiseInfixExpression(operator string, left object.Object, right object.Object) object.Object {
+	if left.GetType() != object.INTEGER_OBJECT || right.GetType() != object.INTEGER_OBJECT {
+		return newErrorOfKind(
+			object.TYPE_ERROR,
+			"bitwise operator %s requires INTEGER operands, got %s and %s",
+			operator,
+			left.GetType(),
+			right.GetType(),
+		)
 	}
 
-	leftVal := left.(*object.String).Value
-	rightVal := right.(*object.String).Value
-	return &object.String{Value: leftVal + rightVal}
-}
+	leftValue := left.(*object.Integer).Value
+	rightValue := right.(*object.Integer).Value
 
-func evaluateIfExpression(expression *ast.IfExpression, environment *object.Environment) object.Object {
-	condition := Evaluate(expression.Condition, environment)
-	if isError(condition) {
-		return condition
-	}
+	switch operator {
 
-	if isTruthy(condition) {
-		return Evaluate(expression.Consequence, environment)
-	}
+	case "&":
+		return &object.Integer{Value: leftValue & rightValue}
 
-	if expression.Alternative != nil {
-		return Evaluate(expression.Alternative, environment)
-	}
+	case "|":
+		return &object.Integer{Value: leftValue | rightValue}
 
-	return NULL
-}
+	case "^":
+		return &object.Integer{Value: leftValue ^ rightValue}
 
-func applyFunction(fn object.Object, arguments []object.Object) object.Object {
-	switch function := fn.(type) {
+	case "<<":
+		if rightValue < 0 {
+			return newErrorOfKind(object.RANGE_ERROR, "negative shift count: %d << %d", leftValue, rightValue)
+		}
 
-	case *object.Function:
-		extendedEnvironment := extendFunctionEnvironment(function, arguments)
-		evaluated := Evaluate(function.Body, extendedEnvironment)
-		return unwrapReturnValue(evaluated)
+		return &object.Integer{Value: leftValue << rightValue}
 
-	case *object.Builtin:
-		return function.Function(arguments...)
+	case ">>":
+		if rightValue < 0 {
+			return newErrorOfKind(object.RANGE_ERROR, "negative shift count: %d >> %d", leftValue, rightValue)
+		}
+
+		return &object.Integer{Value: leftValue >> rightValue}
 
 	default:
-		return newError("not a function: %s", fn.GetType())
+		return newErrorOfKind(object.TYPE_ERROR, "unknown operator: %s %s %s", left.GetType(), operator, right.GetType())
 
 	}
 }
 
-func extendFunctionEnvironment(function *object.Function, arguments []object.Object) *object.Environment {
-	environment := object.NewEnclosedEnvironment(function.Environment)
-	for index, param := range function.Parameters {
-		environment.Set(param.Value, arguments[index])
-	}
-	return environment
-}
-func unwrapReturnValue(obj object.Object) object.Object {
-	returnValue, ok := obj.(*object.ReturnValue)
-	if ok {
-		return returnValue.Value
-	}
+func evaluateFloatInfixExpression(operator string, left object.Object, right object.Object) object.Object {
+	leftValue := toFloat(left)
+	rightValue := toFloat(right)
 
-	return obj
-}
+	switch operator {
 
-func evaluateIndexExpression(left object.Object, index object.Object) object.Object {
-	switch {
+	case "+":
+		return &object.Float{Value: leftValue + rightValue}
 
-	case left.GetType() == object.ARRAY_OBJECT && index.GetType() == object.INTEGER_OBJECT:
+	case "-":
+		return &object.Float{Value: leftValue - rightValue}
+
+	case "*":
+		return &object.Float{Value: leftValue * rightValue}
+
+	case "/":
+		return &object.Float{Value: leftValue / rightValue}
+
+	case "%":
+		return &object.Float{Value: math.Mod(leftValue, rightValue)}
+
+	case "**":
+		return &object.Float{Value: math.Pow(leftValue, rightValue)}
+
+	case "<":
+		return newBooleanObject(leftValue < rightValue)
+
+	case ">":
+		return newBooleanObject(leftValue > rightValue)
+
+	case "<=":
+		return newBooleanObject(leftValue <= rightValue)
+
+	case ">=":
+		return newBooleanObject(leftValue >= rightValue)
+
+	case "==":
+		return newBooleanObject(leftValue == rightValue)
+
+	case "!=":
+		return newBooleanObject(leftValue != rightValue)
+
+	default:
+		return newErrorOfKind(object.TYPE_ERROR, "unknown operator: %s %s %s", left.GetType(), operator, right.GetType())
+
+	}
+}
+
+func evaluateStringInfixExpression(operator string, left object.Object, right object.Object) object.Object {
+	leftValue := left.(*object.String).Value
+	rightValue := right.(*object.String).Value
+
+	switch operator {
+
+	case "+":
+		return &object.String{Value: leftValue + rightValue}
+
+	case "<":
+		return newBooleanObject(leftValue < rightValue)
+
+	case ">":
+		return newBooleanObject(leftValue > rightValue)
+
+	case "<=":
+		return newBooleanObject(leftValue <= rightValue)
+
+	case ">=":
+		return newBooleanObject(leftValue >= rightValue)
+
+	case "==":
+		return newBooleanObject(leftValue == rightValue)
+
+	case "!=":
+		return newBooleanObject(leftValue != rightValue)
+
+	default:
+		return newErrorOfKind(object.TYPE_ERROR, "unknown operator: %s %s %s", left.GetType(), operator, right.GetType())
+
+	}
+}
+
+func evaluateLogicalExpression(expression *ast.LogicalExpression, environment *object.Environment) object.Object {
+	left := Evaluate(expression.LeftExpression, environment)
+	if isInterrupting(left) {
+		return left
+	}
+
+	switch expression.Operator {
+
+	case "&&":
+		if !isTruthy(left) {
+			return FALSE
+		}
+
+	case "||":
+		if isTruthy(left) {
+			return TRUE
+		}
+
+	case "??":
+		if left != NULL {
+			return left
+		}
+
+		// Unlike the boolean operators, the right side is returned as is
+		return Evaluate(expression.RightExpression, environment)
+
+	default:
+		return newErrorOfKind(object.TYPE_ERROR, "unknown operator: %s %s", left.GetType(), expression.Operator)
+
+	}
+
+	right := Evaluate(expression.RightExpression, environment)
+	if isInterrupting(right) {
+		return right
+	}
+
+	return newBooleanObject(isTruthy(right))
+}
+
+func evaluateAssignmentExpression(expression *ast.AssignmentExpression, environment *object.Environment) object.Object {
+	switch target := expression.Target.(type) {
+
+	case *ast.Identifier:
+		return evaluateIdentifierAssignment(target, expression, environment)
+
+	case *ast.IndexExpression:
+		return evaluateIndexAssignment(target, expression, environment)
+
+	default:
+		return newErrorOfKind(object.TYPE_ERROR, "invalid assignment target: %s", expression.Target.String())
+
+	}
+}
+
+func evaluateIdentifierAssignment(
+	identifier *ast.Identifier,
+	expression *ast.AssignmentExpression,
+	environment *object.Environment,
+) object.Object {
+	value := Evaluate(expression.Value, environment)
+	if isInterrupting(value) {
+		return value
+	}
+
+	if expression.Operator != "=" {
+		current := evaluateIdentifier(identifier, environment)
+		if isError(current) {
+			return current
+		}
+
+		value = evaluateCompoundAssignment(expression.Operator, current, value, environment)
+		if isError(value) {
+			return value
+		}
+	}
+
+	if _, ok := environment.Assign(identifier.Value, value); !ok {
+		return newErrorOfKind(object.REFERENCE_ERROR, "assignment to undeclared identifier: "+identifier.Value)
+	}
+
+	return value
+}
+
+func evaluateIndexAssignment(
+	target *ast.IndexExpression,
+	expression *ast.AssignmentExpression,
+	environment *object.Environment,
+) object.Object {
+	left := Evaluate(target.Left, environment)
+	if isInterrupting(left) {
+		return left
+	}
+
+	index := Evaluate(target.Index, environment)
+	if isInterrupting(index) {
+		return index
+	}
+
+	value := Evaluate(expression.Value, environment)
+	if isInterrupting(value) {
+		return value
+	}
+
+	if expression.Operator != "=" {
+		current := evaluateIndexExpression(left, index)
+		if isError(current) {
+			return current
+		}
+
+		value = evaluateCompoundAssignment(expression.Operator, current, value, environment)
+		if isError(value) {
+			return value
+		}
+	}
+
+	switch {
+
+	case left.GetType() == object.ARRAY_OBJECT && index.GetType() == object.INTEGER_OBJECT:
+		return assignArrayIndex(left.(*object.Array), index.(*object.Integer).Value, value)
+
+	case left.GetType() == object.HASH_OBJECT:
+		return assignHashIndex(left.(*object.Hash), index, value)
+
+	default:
+		return newErrorOfKind(object.TYPE_ERROR, "index assignment not supported: %s", left.GetType())
+
+	}
+}
+
+// Compound operators apply their infix operator before assigning
+func evaluateCompoundAssignment(
+	operator string,
+	current object.Object,
+	value object.Object,
+	environment *object.Environment,
+) object.Object {
+	return evaluateInfixExpression(strings.TrimSuffix(operator, "="), current, value, environment)
+}
+
+// Assigning right after the last element grows the array by one
+func assignArrayIndex(array *object.Array, index int64, value object.Object) object.Object {
+	length := int64(len(array.Elements))
+
+	switch {
+
+	case index >= 0 && index < length:
+		array.Elements[index] = value
+
+	case index == length:
+		array.Elements = append(array.Elements, value)
+
+	default:
+		return newErrorOfKind(object.RANGE_ERROR, "index out of range: %d with length %d", index, length)
+
+	}
+
+	return value
+}
+
+func assignHashIndex(hash *object.Hash, index object.Object, value object.Object) object.Object {
+	key, ok := index.(object.Hashable)
+	if !ok {
+		return newErrorOfKind(object.TYPE_ERROR, "unusable as hash key: %s", index.GetType())
+	}
+
+	hash.Pairs[key.HashKey()] = object.HashPair{
+		Key:   index,
+		Value: value,
+	}
+
+	return value
+}
+
+func evaluateIfExpression(expression *ast.IfExpression, environment *object.Environment) object.Object {
+	condition := Evaluate(expression.Condition, environment)
+	if isInterrupting(condition) {
+		return condition
+	}
+
+	if isTruthy(condition) {
+		return Evaluate(expression.Consequence, environment)
+	}
+
+	if expression.Alternative != nil {
+		return Evaluate(expression.Alternative, environment)
+	}
+
+	return NULL
+}
+
+func evaluateConditionalExpression(
+	expression *ast.ConditionalExpression,
+	environment *object.Environment,
+) object.Object {
+	condition := Evaluate(expression.Condition, environment)
+	if isInterrupting(condition) {
+		return condition
+	}
+
+	if isTruthy(condition) {
+		return Evaluate(expression.Consequence, environment)
+	}
+
+	return Evaluate(expression.Alternative, environment)
+}
+
+func evaluateMatchExpression(expression *ast.MatchExpression, environment *object.Environment) object.Object {
+	subject := Evaluate(expression.Subject, environment)
+	if isInterrupting(subject) {
+		return subject
+	}
+
+	for _, arm := range expression.Arms {
+		armEnvironment := object.NewEnclosedEnvironment(environment)
+
+		// Arms whose pattern cannot destructure the subject are skipped
+		if bindPattern(arm.Pattern, subject, armEnvironment) != nil {
+			continue
+		}
+
+		if body, ok := arm.Body.(*ast.BlockStatement); ok {
+			return evaluateBlockStatement(body, armEnvironment)
+		}
+
+		return Evaluate(arm.Body, armEnvironment)
+	}
+
+	return newErrorOfKind(object.VALUE_ERROR, "no match arm for value %s", subject.Inspect())
+}
+
+func applyFunction(
+	fn object.Object,
+	arguments []object.Object,
+	namedArguments map[string]object.Object,
+	call *ast.CallExpression,
+	environment *object.Environment,
+) object.Object {
+	switch function := fn.(type) {
+
+	case *object.Function:
+		extendedEnvironment, err := extendFunctionEnvironment(function, arguments, namedArguments)
+		if err != nil {
+			return err
+		}
+
+		evaluated := Evaluate(function.Body, extendedEnvironment)
+
+		// Recording the call the error is propagating through
+		if err, ok := evaluated.(*object.Error); ok {
+			name := function.Name
+			if name == "" {
+				name = "<anonymous>"
+			}
+
+			// Pointing to the callee rather than the opening parenthesis
+			callee := ast.StartToken(call.Function)
+			err.Stack = append(err.Stack, object.StackFrame{
+				Function: name,
+				File:     environment.Filepath,
+				Line:     callee.Line,
+				Position: callee.Position,
+			})
+		}
+
+		return unwrapReturnValue(evaluated)
+
+	case *object.Builtin:
+		if len(namedArguments) > 0 {
+			return newErrorOfKind(object.ARGUMENT_ERROR, "builtin functions do not accept named arguments")
+		}
+
+		return function.Function(arguments...)
+
+	default:
+		return newErrorOfKind(object.TYPE_ERROR, "not a function: %s", fn.GetType())
+
+	}
+}
+
+func extendFunctionEnvironment(
+	function *object.Function,
+	arguments []object.Object,
+	namedArguments map[string]object.Object,
+) (*object.Environment, *object.Error) {
+	environment := object.NewEnclosedEnvironment(function.Environment)
+
+	err := checkNamedArguments(function, namedArguments)
+	if err != nil {
+		return nil, err
+	}
+
+	for index, parameter := range function.Parameters {
+		if parameter.Rest {
+			rest := []object.Object{}
+			if index < len(arguments) {
+				rest = append(rest, arguments[index:]...)
+			}
+
+			err := bindPattern(parameter.Pattern, &object.Array{Elements: rest}, environment)
+			if err != nil {
+				return nil, err
+			}
+
+			return environment, nil
+		}
+
+		value, isNamed := getNamedArgument(parameter, namedArguments)
+
+		switch {
+
+		case index < len(arguments) && isNamed:
+			return nil, newErrorOfKind(object.ARGUMENT_ERROR, "multiple values for argument %s", parameter.Pattern.String())
+
+		case index < len(arguments):
+			value = arguments[index]
+
+		// Defaults are evaluated in the function environment, so they can refer to previous parameters
+		case !isNamed && parameter.Default != nil:
+			value = Evaluate(parameter.Default, environment)
+			if isError(value) {
+				return nil, value.(*object.Error)
+			}
+
+		case !isNamed:
+			return nil, newErrorOfKind(object.ARGUMENT_ERROR, "missing argument for parameter %s", parameter.Pattern.String())
+
+		}
+
+		err := bindPattern(parameter.Pattern, value, environment)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(arguments) > len(function.Parameters) {
+		return nil, newErrorOfKind(
+			object.ARGUMENT_ERROR,
+			"wrong number of arguments. got=%d, want=%d",
+			len(arguments),
+			len(function.Parameters),
+		)
+	}
+
+	return environment, nil
+}
+
+// Only identifier parameters can be passed by name
+func getNamedArgument(parameter *ast.Parameter, namedArguments map[string]object.Object) (object.Object, bool) {
+	identifier, ok := parameter.Pattern.(*ast.Identifier)
+	if !ok {
+		return nil, false
+	}
+
+	value, ok := namedArguments[identifier.Value]
+	return value, ok
+}
+
+func checkNamedArguments(function *object.Function, namedArguments map[string]object.Object) *object.Error {
+	unknownNames := []string{}
+
+	for name := range namedArguments {
+		isKnown := false
+		for _, parameter := range function.Parameters {
+			identifier, ok := parameter.Pattern.(*ast.Identifier)
+			if ok && !parameter.Rest && identifier.Value == name {
+				isKnown = true
+			}
+		}
+
+		if !isKnown {
+			unknownNames = append(unknownNames, name)
+		}
+	}
+
+	if len(unknownNames) == 0 {
+		return nil
+	}
+
+	sort.Strings(unknownNames)
+	return newErrorOfKind(object.ARGUMENT_ERROR, "unknown named arguments: %s", strings.Join(unknownNames, ", "))
+}
+
+// Calls evaluate to null when the body ends without a value, as empty bodies do
+func unwrapReturnValue(obj object.Object) object.Object {
+	if returnValue, ok := obj.(*object.ReturnValue); ok {
+		obj = returnValue.Value
+	}
+
+	if obj == nil {
+		return NULL
+	}
+
+	return obj
+}
+
+func evaluateIndexExpression(left object.Object, index object.Object) object.Object {
+	switch {
+
+	case left.GetType() == object.ARRAY_OBJECT && index.GetType() == object.INTEGER_OBJECT:
 		return evaluateArrayIndexExpression(left, index)
 
 	case left.GetType() == object.HASH_OBJECT:
 		return evaluateHashIndexExpression(left, index)
 
 	default:
-		return newError("index operator not supported: %s", left.GetType())
+		return newErrorOfKind(object.TYPE_ERROR, "index operator not supported: %s", left.GetType())
 
 	}
 }
@@ -447,17 +1371,17 @@ func evaluateHashLiteral(hash *ast.HashLiteral, environment *object.Environment)
 
 	for keyExpression, valueExpression := range hash.Pairs {
 		key := Evaluate(keyExpression, environment)
-		if isError(key) {
+		if isInterrupting(key) {
 			return key
 		}
 
 		hashKey, ok := key.(object.Hashable)
 		if !ok {
-			return newError("unusable as hash key: %s", key.GetType())
+			return newErrorOfKind(object.TYPE_ERROR, "unusable as hash key: %s", key.GetType())
 		}
 
 		value := Evaluate(valueExpression, environment)
-		if isError(value) {
+		if isInterrupting(value) {
 			return value
 		}
 
@@ -476,7 +1400,7 @@ func evaluateHashIndexExpression(hash object.Object, index object.Object) object
 
 	key, ok := index.(object.Hashable)
 	if !ok {
-		return newError("unusable as hash key: %s", index.GetType())
+		return newErrorOfKind(object.TYPE_ERROR, "unusable as hash key: %s", index.GetType())
 	}
 
 	pair, ok := hashObject.Pairs[key.HashKey()]
@@ -488,55 +1412,88 @@ func evaluateHashIndexExpression(hash object.Object, index object.Object) object
 }
 
 func evaluateAccessExpression(expression *ast.AccessExpression, environment *object.Environment) object.Object {
-	accessor := Evaluate(expression.Accessor, environment)
-	if isError(accessor) {
+	accessor := evaluateChainLink(expression.Accessor, environment)
+	if isError(accessor) || isSkippedChain(accessor) {
 		return accessor
 	}
 
 	switch {
 
+	case expression.Optional && accessor == NULL:
+		return SKIPPED_CHAIN
+
 	case accessor.GetType() == object.IMPORT_OBJECT:
 		return evaluateImportAccessExpression(accessor, expression.Accessed, environment)
 
+	case accessor.GetType() == object.HASH_OBJECT:
+		return evaluateHashIndexExpression(accessor, &object.String{Value: expression.Accessed.Value})
+
+	case accessor.GetType() == object.EXCEPTION_OBJECT:
+		return evaluateExceptionAccessExpression(accessor.(*object.Exception), expression.Accessed)
+
 	default:
-		return newError("unsuported access type %s", accessor.GetType())
+		return newErrorOfKind(object.TYPE_ERROR, "unsuported access type %s", accessor.GetType())
 
 	}
 }
 
-func evaluateImportAccessExpression(
-	accessor object.Object,
-	accessed ast.Expression,
-	environment *object.Environment,
-) object.Object {
-	importObject := accessor.(*object.Import)
+func evaluateExceptionAccessExpression(exception *object.Exception, accessed *ast.Identifier) object.Object {
+	err := exception.Error
 
-	switch accessed := accessed.(type) {
+	switch accessed.Value {
 
-	case *ast.CallExpression:
-		identifier := accessed.Function.(*ast.Identifier)
-		function, ok := environment.GetModuleValue(importObject.Path, identifier.Value)
-		if !ok {
-			return newError("Couldn't find '%s' from file : %s", identifier.Value, importObject.Path)
-		}
+	case "message":
+		return &object.String{Value: err.Message}
+
+	case "kind":
+		return &object.String{Value: err.Kind}
+
+	case "file":
+		return &object.String{Value: err.File}
+
+	case "line":
+		return &object.Integer{Value: int64(err.Line)}
 
-		arguments := evaluateExpressions(accessed.Arguments, environment)
-		if len(arguments) == 1 && isError(arguments[0]) {
-			return arguments[0]
+	case "position":
+		return &object.Integer{Value: int64(err.Position)}
+
+	case "value":
+		if err.Value == nil {
+			return NULL
 		}
 
-		return applyFunction(function, arguments)
+		return err.Value
 
 	default:
-		return newError("Import access not supported for : %s", accessed.String())
+		return newErrorOfKind(object.TYPE_ERROR, "unknown exception field %s", accessed.Value)
 
 	}
 }
 
+func evaluateImportAccessExpression(
+	accessor object.Object,
+	accessed *ast.Identifier,
+	environment *object.Environment,
+) object.Object {
+	importObject := accessor.(*object.Import)
+
+	value, ok := environment.GetModuleValue(importObject.Path, accessed.Value)
+	if !ok {
+		return newErrorOfKind(object.REFERENCE_ERROR, "Couldn't find '%s' from file : %s", accessed.Value, importObject.Path)
+	}
+
+	return value
+}
+
 // Utils
 
 func newError(format string, a ...interface{}) *object.Error {
+	return newErrorOfKind(object.GENERIC_ERROR, format, a...)
+}
+
+func newErrorOfKind(kind string, format string, a ...interface{}) *object.Error {
 	return &object.Error{
+		Kind:    kind,
 		Message: fmt.Sprintf(format, a...),
 	}
 }
@@ -567,6 +1524,38 @@ func isTruthy(object object.Object) bool {
 	}
 }
 
+func isBitwiseOperator(operator string) bool {
+	switch operator {
+
+	case "&", "|", "^", "<<", ">>":
+		return true
+
+	default:
+		return false
+
+	}
+}
+
+func isNumber(obj object.Object) bool {
+	objectType := obj.GetType()
+	return objectType == object.INTEGER_OBJECT || objectType == object.FLOAT_OBJECT
+}
+
+func toFloat(obj object.Object) float64 {
+	switch obj := obj.(type) {
+
+	case *object.Integer:
+		return float64(obj.Value)
+
+	case *object.Float:
+		return obj.Value
+
+	default:
+		return 0
+
+	}
+}
+
 func isError(obj object.Object) bool {
 	if obj != nil {
 		return obj.GetType() == object.ERROR_OBJECT
@@ -574,3 +1563,28 @@ func isError(obj object.Object) bool {
 
 	return false
 }
+
+// Errors and the return, break and continue signals stop the evaluation of
+// the expressions they come from, and are passed up as they are
+func isInterrupting(obj object.Object) bool {
+	if obj == nil {
+		return false
+	}
+
+	switch obj.GetType() {
+
+	case object.ERROR_OBJECT, object.RETURN_VALUE_OBJECT, object.BREAK_OBJECT, object.CONTINUE_OBJECT:
+		return true
+
+	}
+
+	return false
+}
+
+func isSkippedChain(obj object.Object) bool {
+	if obj != nil {
+		return obj.GetType() == object.SKIPPED_CHAIN_OBJECT
+	}
+
+	return false
+}
@@ -0,0 +1,137 @@
+package evaluator
+
+import (
+	"glass/language/ast"
+	"glass/language/object"
+)
+
+// Binds the identifiers of a pattern into the environment, or describes why the value does not match it
+func bindPattern(pattern ast.Expression, value object.Object, environment *object.Environment) *object.Error {
+	switch pattern := pattern.(type) {
+
+	case *ast.Identifier:
+		// The "_" wildcard matches any value without binding it
+		if pattern.Value != "_" {
+			environment.Set(pattern.Value, value)
+		}
+
+		return nil
+
+	case *ast.ArrayPattern:
+		return bindArrayPattern(pattern, value, environment)
+
+	case *ast.HashPattern:
+		return bindHashPattern(pattern, value, environment)
+
+	default:
+		literal := Evaluate(pattern, environment)
+		if isError(literal) {
+			return literal.(*object.Error)
+		}
+
+		if !isLiteralMatching(literal, value) {
+			return newErrorOfKind(object.VALUE_ERROR, "cannot destructure %s into %s", value.Inspect(), pattern.String())
+		}
+
+		return nil
+
+	}
+}
+
+func bindArrayPattern(pattern *ast.ArrayPattern, value object.Object, environment *object.Environment) *object.Error {
+	array, ok := value.(*object.Array)
+	if !ok {
+		return newErrorOfKind(object.VALUE_ERROR, "cannot destructure %s into %s", value.GetType(), pattern.String())
+	}
+
+	length := len(array.Elements)
+	if length < len(pattern.Elements) || (pattern.Rest == nil && length > len(pattern.Elements)) {
+		return newErrorOfKind(object.VALUE_ERROR, "cannot destructure array of length %d into %s", length, pattern.String())
+	}
+
+	for index, element := range pattern.Elements {
+		err := bindPattern(element, array.Elements[index], environment)
+		if err != nil {
+			return err
+		}
+	}
+
+	if pattern.Rest != nil {
+		rest := make([]object.Object, length-len(pattern.Elements))
+		copy(rest, array.Elements[len(pattern.Elements):])
+		return bindPattern(pattern.Rest, &object.Array{Elements: rest}, environment)
+	}
+
+	return nil
+}
+
+func bindHashPattern(pattern *ast.HashPattern, value object.Object, environment *object.Environment) *object.Error {
+	hash, ok := value.(*object.Hash)
+	if !ok {
+		return newErrorOfKind(object.VALUE_ERROR, "cannot destructure %s into %s", value.GetType(), pattern.String())
+	}
+
+	boundKeys := make(map[object.HashKey]bool)
+
+	for _, pair := range pattern.Pairs {
+		key := Evaluate(pair.Key, environment)
+		if isError(key) {
+			return key.(*object.Error)
+		}
+
+		hashKey, ok := key.(object.Hashable)
+		if !ok {
+			return newErrorOfKind(object.TYPE_ERROR, "unusable as hash key: %s", key.GetType())
+		}
+
+		hashPair, ok := hash.Pairs[hashKey.HashKey()]
+		if !ok {
+			return newErrorOfKind(object.VALUE_ERROR, "cannot destructure hash without key %s into %s", key.Inspect(), pattern.String())
+		}
+
+		err := bindPattern(pair.Value, hashPair.Value, environment)
+		if err != nil {
+			return err
+		}
+
+		boundKeys[hashKey.HashKey()] = true
+	}
+
+	if pattern.Rest != nil {
+		rest := make(map[object.HashKey]object.HashPair)
+		for hashKey, hashPair := range hash.Pairs {
+			if !boundKeys[hashKey] {
+				rest[hashKey] = hashPair
+			}
+		}
+
+		return bindPattern(pattern.Rest, &object.Hash{Pairs: rest}, environment)
+	}
+
+	return nil
+}
+
+func isLiteralMatching(literal object.Object, value object.Object) bool {
+	switch literal := literal.(type) {
+
+	case *object.Integer:
+		if value, ok := value.(*object.Integer); ok {
+			return value.Value == literal.Value
+		}
+
+		// Numbers match across integers and floats, as they compare equal
+		return isNumber(value) && toFloat(value) == toFloat(literal)
+
+	case *object.Float:
+		return isNumber(value) && toFloat(value) == literal.Value
+
+	case *object.String:
+		value, ok := value.(*object.String)
+		return ok && value.Value == literal.Value
+
+	default:
+		// Booleans and null are singletons
+		return literal == value
+
+	}
+}
@@ -1,21 +1,31 @@
 package lexer
 
 import (
+	"fmt"
 	token "glass/language/token"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
+// Lines are read as runes, so positions are counted in characters rather than bytes
 type Lexer struct {
-	line         string
+	line         []rune
 	lineNumber   int
 	position     int
 	readPosition int
-	character    byte
+	character    rune
 	getNextLine  func() (string, bool)
+	keepComments bool
+
+	// Braces opened inside each string interpolation being read
+	interpolationBraceDepths []int
 }
 
 func New(line string, getNextLine func() (string, bool)) *Lexer {
 	lexer := &Lexer{
-		line:        line,
+		line:        []rune(line),
 		lineNumber:  1,
 		getNextLine: getNextLine,
 	}
@@ -25,25 +35,20 @@ func New(line string, getNextLine func() (string, bool)) *Lexer {
 	return lexer
 }
 
-func (lexer *Lexer) readCharacter() {
-	if lexer.readPosition >= len(lexer.line) {
-		nextLine, isFileEnd := lexer.getNextLine()
-		lexer.lineNumber++
-
-		for nextLine == "" {
-			if isFileEnd {
-				lexer.character = 0
-				return
-			}
+// Comments are skipped unless kept, in which case they are returned as COMMENT tokens
+func (lexer *Lexer) SetKeepComments(keepComments bool) {
+	lexer.keepComments = keepComments
+}
 
-			nextLine, isFileEnd = lexer.getNextLine()
-			lexer.lineNumber++
-		}
+func (lexer *Lexer) readCharacter() {
+	if lexer.readPosition > len(lexer.line) && !lexer.readNextLine() {
+		lexer.character = 0
+		return
+	}
 
-		lexer.line = nextLine
-		lexer.position = 0
-		lexer.readPosition = 0
-		lexer.character = lexer.line[lexer.readPosition]
+	// Line ends are read as a newline before moving to the next line
+	if lexer.readPosition == len(lexer.line) {
+		lexer.character = '\n'
 	} else {
 		lexer.character = lexer.line[lexer.readPosition]
 	}
@@ -52,14 +57,44 @@ func (lexer *Lexer) readCharacter() {
 	lexer.readPosition += 1
 }
 
-func (lexer *Lexer) peekCharacter() byte {
-	if lexer.readPosition >= len(lexer.line) {
+func (lexer *Lexer) readNextLine() bool {
+	if lexer.getNextLine == nil {
+		return false
+	}
+
+	nextLine, isFileEnd := lexer.getNextLine()
+	if isFileEnd {
+		return false
+	}
+
+	lexer.line = []rune(nextLine)
+	lexer.lineNumber++
+	lexer.readPosition = 0
+
+	return true
+}
+
+func (lexer *Lexer) peekCharacter() rune {
+	if lexer.readPosition == len(lexer.line) {
+		return '\n'
+	}
+
+	if lexer.readPosition > len(lexer.line) {
 		return 0
 	}
 
 	return lexer.line[lexer.readPosition]
 }
 
+// Returns the character after the peeked one, or 0 when it is past the end of the line
+func (lexer *Lexer) peekSecondCharacter() rune {
+	if lexer.readPosition+1 >= len(lexer.line) {
+		return 0
+	}
+
+	return lexer.line[lexer.readPosition+1]
+}
+
 func (lexer *Lexer) Next() token.Token {
 
 	lexer.skipWhitespace()
@@ -73,13 +108,23 @@ func (lexer *Lexer) Next() token.Token {
 	switch lexer.character {
 
 	case '=':
-		if lexer.peekCharacter() == '=' {
+		switch lexer.peekCharacter() {
+
+		case '=':
 			// Advance to peeked character
 			lexer.readCharacter()
 			nextToken.Type = token.EQUALS
 			nextToken.Literal = "=="
-		} else {
+
+		case '>':
+			// Advance to peeked character
+			lexer.readCharacter()
+			nextToken.Type = token.FAT_ARROW
+			nextToken.Literal = "=>"
+
+		default:
 			nextToken.Type = token.ASSIGN
+
 		}
 
 	case '!':
@@ -87,11 +132,57 @@ func (lexer *Lexer) Next() token.Token {
 			// Advance to peeked character
 			lexer.readCharacter()
 			nextToken.Type = token.NOT_EQUALS
-			nextToken.Literal = "=="
+			nextToken.Literal = "!="
 		} else {
 			nextToken.Type = token.NOT
 		}
 
+	case '&':
+		if lexer.peekCharacter() == '&' {
+			// Advance to peeked character
+			lexer.readCharacter()
+			nextToken.Type = token.AND
+			nextToken.Literal = "&&"
+		} else {
+			nextToken.Type = token.AMPERSAND
+		}
+
+	case '|':
+		if lexer.peekCharacter() == '|' {
+			// Advance to peeked character
+			lexer.readCharacter()
+			nextToken.Type = token.OR
+			nextToken.Literal = "||"
+		} else {
+			nextToken.Type = token.PIPE
+		}
+
+	case '?':
+		switch lexer.peekCharacter() {
+
+		case '.':
+			// Advance to peeked character
+			lexer.readCharacter()
+			nextToken.Type = token.QUESTION_DOT
+			nextToken.Literal = "?."
+
+		case '?':
+			// Advance to peeked character
+			lexer.readCharacter()
+			nextToken.Type = token.NULLISH
+			nextToken.Literal = "??"
+
+		default:
+			nextToken.Type = token.QUESTION
+
+		}
+
+	case '^':
+		nextToken.Type = token.CARET
+
+	case '~':
+		nextToken.Type = token.TILDE
+
 	case ';':
 		nextToken.Type = token.SEMICOLON
 
@@ -102,23 +193,59 @@ func (lexer *Lexer) Next() token.Token {
 		nextToken.Type = token.RPAREN
 
 	case '.':
-		nextToken.Type = token.DOT
+		if lexer.peekCharacter() == '.' && lexer.peekSecondCharacter() == '.' {
+			// Advance to the last peeked character
+			lexer.readCharacter()
+			lexer.readCharacter()
+			nextToken.Type = token.ELLIPSIS
+			nextToken.Literal = "..."
+		} else {
+			nextToken.Type = token.DOT
+		}
 
 	case ',':
 		nextToken.Type = token.COMMA
 
 	case '+':
-		nextToken.Type = token.PLUS
+		if lexer.peekCharacter() == '=' {
+			// Advance to peeked character
+			lexer.readCharacter()
+			nextToken.Type = token.PLUS_ASSIGN
+			nextToken.Literal = "+="
+		} else {
+			nextToken.Type = token.PLUS
+		}
 
 	case '-':
-		nextToken.Type = token.MINUS
+		if lexer.peekCharacter() == '=' {
+			// Advance to peeked character
+			lexer.readCharacter()
+			nextToken.Type = token.MINUS_ASSIGN
+			nextToken.Literal = "-="
+		} else {
+			nextToken.Type = token.MINUS
+		}
 
 	case '{':
 		nextToken.Type = token.LBRACE
 
+		if depths := lexer.interpolationBraceDepths; len(depths) > 0 {
+			depths[len(depths)-1]++
+		}
+
 	case '}':
 		nextToken.Type = token.RBRACE
 
+		if depths := lexer.interpolationBraceDepths; len(depths) > 0 {
+			// Closing an interpolation resumes reading its string
+			if depths[len(depths)-1] == 0 {
+				lexer.interpolationBraceDepths = depths[:len(depths)-1]
+				return lexer.readString(nextToken)
+			}
+
+			depths[len(depths)-1]--
+		}
+
 	case '[':
 		nextToken.Type = token.LBRACKET
 
@@ -129,35 +256,101 @@ func (lexer *Lexer) Next() token.Token {
 		nextToken.Type = token.COLON
 
 	case '/':
-		nextToken.Type = token.SLASH
+		if lexer.peekCharacter() == '/' || lexer.peekCharacter() == '*' {
+			return lexer.readComment(nextToken)
+		}
+
+		if lexer.peekCharacter() == '=' {
+			// Advance to peeked character
+			lexer.readCharacter()
+			nextToken.Type = token.SLASH_ASSIGN
+			nextToken.Literal = "/="
+		} else {
+			nextToken.Type = token.SLASH
+		}
 
 	case '*':
-		nextToken.Type = token.ASTERISK
+		switch lexer.peekCharacter() {
+
+		case '=':
+			// Advance to peeked character
+			lexer.readCharacter()
+			nextToken.Type = token.ASTERISK_ASSIGN
+			nextToken.Literal = "*="
+
+		case '*':
+			// Advance to peeked character
+			lexer.readCharacter()
+			nextToken.Type = token.POWER
+			nextToken.Literal = "**"
+
+		default:
+			nextToken.Type = token.ASTERISK
+
+		}
+
+	case '%':
+		nextToken.Type = token.PERCENT
 
 	case '>':
-		nextToken.Type = token.GREATER_THAN
+		switch lexer.peekCharacter() {
+
+		case '=':
+			// Advance to peeked character
+			lexer.readCharacter()
+			nextToken.Type = token.GREATER_EQUALS
+			nextToken.Literal = ">="
+
+		case '>':
+			// Advance to peeked character
+			lexer.readCharacter()
+			nextToken.Type = token.SHIFT_RIGHT
+			nextToken.Literal = ">>"
+
+		default:
+			nextToken.Type = token.GREATER_THAN
+
+		}
 
 	case '<':
-		nextToken.Type = token.LESS_THAN
+		switch lexer.peekCharacter() {
+
+		case '=':
+			// Advance to peeked character
+			lexer.readCharacter()
+			nextToken.Type = token.LESS_EQUALS
+			nextToken.Literal = "<="
+
+		case '<':
+			// Advance to peeked character
+			lexer.readCharacter()
+			nextToken.Type = token.SHIFT_LEFT
+			nextToken.Literal = "<<"
+
+		default:
+			nextToken.Type = token.LESS_THAN
+
+		}
 
 	case '"':
-		nextToken.Type = token.STRING
-		nextToken.Literal = lexer.readString()
+		return lexer.readString(nextToken)
+
+	case '`':
+		return lexer.readRawString(nextToken)
 
 	case 0:
 		nextToken.Literal = ""
 		nextToken.Type = token.EOF
 
 	default:
-		if isValidCharacter(lexer.character) {
+		if isLetter(lexer.character) {
 			nextToken.Literal = lexer.readIdentifier()
 			nextToken.Type = token.LookupIdentifier(nextToken.Literal)
 			return nextToken
 		}
 
 		if isDigit(lexer.character) {
-			nextToken.Type = token.INT
-			nextToken.Literal = lexer.readNumber()
+			nextToken.Literal, nextToken.Type = lexer.readNumber()
 			return nextToken
 		}
 
@@ -168,43 +361,297 @@ func (lexer *Lexer) Next() token.Token {
 	return nextToken
 }
 
-func isValidCharacter(character byte) bool {
-	return ('a' <= character && character <= 'z') ||
-		('A' <= character && character <= 'Z') ||
-		character == '_'
+func isLetter(character rune) bool {
+	return unicode.IsLetter(character) || character == '_'
+}
+
+// Identifiers start with a letter, but can then contain digits
+func isIdentifierCharacter(character rune) bool {
+	return isLetter(character) || unicode.IsDigit(character)
 }
 
-func isDigit(character byte) bool {
+func isDigit(character rune) bool {
 	return '0' <= character && character <= '9'
 }
 
+func isHexadecimalDigit(character rune) bool {
+	return isDigit(character) ||
+		('a' <= character && character <= 'f') ||
+		('A' <= character && character <= 'F')
+}
+
 func (lexer *Lexer) readIdentifier() string {
 	position := lexer.position
-	for isValidCharacter(lexer.character) {
+	for isIdentifierCharacter(lexer.character) {
 		lexer.readCharacter()
 	}
 
-	return lexer.line[position:lexer.position]
+	return string(lexer.line[position:lexer.position])
 }
 
-func (lexer *Lexer) readNumber() string {
+func (lexer *Lexer) readNumber() (string, token.TokenType) {
 	position := lexer.position
-	for isDigit(lexer.character) {
+
+	// Hexadecimal, binary and octal literals, validated when parsed
+	if lexer.character == '0' && strings.ContainsRune("xXbBoO", lexer.peekCharacter()) {
+		lexer.readCharacter()
+		lexer.readCharacter()
+		for isIdentifierCharacter(lexer.character) {
+			lexer.readCharacter()
+		}
+
+		return string(lexer.line[position:lexer.position]), token.INT
+	}
+
+	for isDigit(lexer.character) || lexer.character == '_' {
 		lexer.readCharacter()
 	}
 
-	return lexer.line[position:lexer.position]
+	// A dot only belongs to the number when a digit follows it
+	if lexer.character != '.' || !isDigit(lexer.peekCharacter()) {
+		return string(lexer.line[position:lexer.position]), token.INT
+	}
+
+	lexer.readCharacter()
+	for isDigit(lexer.character) || lexer.character == '_' {
+		lexer.readCharacter()
+	}
+
+	return string(lexer.line[position:lexer.position]), token.FLOAT
 }
 
-func (lexer *Lexer) readString() string {
-	position := lexer.position + 1
+// Strings end on the line they started, and are returned as ILLEGAL when not closed.
+// Interpolations split strings in segments, read from the opening quote or closing brace.
+func (lexer *Lexer) readString(nextToken token.Token) token.Token {
+	var builder strings.Builder
+
+	position := lexer.position
+	isContinuation := lexer.character == '}'
+	invalidEscapePosition := -1
 
 	lexer.readCharacter()
 	for lexer.character != '"' {
+		if lexer.character == '\n' || lexer.character == 0 {
+			nextToken.Type = token.ILLEGAL
+			nextToken.Literal = string(lexer.line[position:lexer.position])
+			nextToken.Reason = "Unterminated string"
+			return nextToken
+		}
+
+		if lexer.character == '$' && lexer.peekCharacter() == '{' {
+			break
+		}
+
+		if lexer.character == '\\' {
+			escapePosition := lexer.position
+			lexer.readCharacter()
+
+			// Leaving the line end to be reported as unterminated
+			if lexer.character == '\n' || lexer.character == 0 {
+				continue
+			}
+
+			escaped, ok := lexer.readEscapeSequence()
+			if !ok && invalidEscapePosition < 0 {
+				invalidEscapePosition = escapePosition
+			}
+
+			builder.WriteString(escaped)
+		} else {
+			builder.WriteRune(lexer.character)
+		}
+
+		lexer.readCharacter()
+	}
+
+	isInterpolation := lexer.character == '$'
+
+	// Closing quote, or opening ${
+	lexer.readCharacter()
+	if isInterpolation {
 		lexer.readCharacter()
+		lexer.interpolationBraceDepths = append(lexer.interpolationBraceDepths, 0)
+	}
+
+	// The first invalid escape sequence is reported in place of the string
+	if invalidEscapePosition >= 0 {
+		nextToken.Type = token.ILLEGAL
+		nextToken.Literal = string(lexer.line[invalidEscapePosition : invalidEscapePosition+2])
+		nextToken.Position = invalidEscapePosition
+		nextToken.Reason = fmt.Sprintf("Invalid escape sequence %q in string", nextToken.Literal)
+		return nextToken
+	}
+
+	switch {
+
+	case !isContinuation && !isInterpolation:
+		nextToken.Type = token.STRING
+
+	case !isContinuation:
+		nextToken.Type = token.TEMPLATE_HEAD
+
+	case isInterpolation:
+		nextToken.Type = token.TEMPLATE_MIDDLE
+
+	default:
+		nextToken.Type = token.TEMPLATE_TAIL
+
+	}
+
+	nextToken.Literal = builder.String()
+	nextToken.Length = lexer.position - position
+	return nextToken
+}
+
+// Raw strings have no escape sequences and can span multiple lines
+func (lexer *Lexer) readRawString(nextToken token.Token) token.Token {
+	var builder strings.Builder
+
+	firstLineLength := len(lexer.line)
+
+	lexer.readCharacter()
+	for lexer.character != '`' {
+		if lexer.character == 0 {
+			nextToken.Type = token.ILLEGAL
+			nextToken.Literal = "`" + builder.String()
+			nextToken.Reason = "Unterminated string"
+			return nextToken
+		}
+
+		builder.WriteRune(lexer.character)
+		lexer.readCharacter()
+	}
+
+	// Closing backtick
+	lexer.readCharacter()
+
+	nextToken.Type = token.STRING
+	nextToken.Literal = builder.String()
+
+	// Strings spanning multiple lines are only covered up to the end of their first line
+	if lexer.lineNumber == nextToken.Line {
+		nextToken.Length = lexer.position - nextToken.Position
+	} else {
+		nextToken.Length = firstLineLength - nextToken.Position
 	}
 
-	return lexer.line[position:lexer.position]
+	return nextToken
+}
+
+// Reads the escape sequence following a backslash, stopping on its last character
+func (lexer *Lexer) readEscapeSequence() (string, bool) {
+	switch lexer.character {
+
+	case 'n':
+		return "\n", true
+
+	case 't':
+		return "\t", true
+
+	case 'r':
+		return "\r", true
+
+	case '0':
+		return "\x00", true
+
+	case '\\', '"', '\'', '$':
+		return string(lexer.character), true
+
+	case 'u':
+		return lexer.readUnicodeEscapeSequence()
+
+	default:
+		return "", false
+
+	}
+}
+
+// Reads either \uXXXX or \u{X...} escape sequences
+func (lexer *Lexer) readUnicodeEscapeSequence() (string, bool) {
+	var digits strings.Builder
+
+	if lexer.peekCharacter() == '{' {
+		lexer.readCharacter()
+		for isHexadecimalDigit(lexer.peekCharacter()) && digits.Len() < 6 {
+			lexer.readCharacter()
+			digits.WriteRune(lexer.character)
+		}
+
+		if lexer.peekCharacter() != '}' {
+			return "", false
+		}
+
+		lexer.readCharacter()
+	} else {
+		for digits.Len() < 4 {
+			if !isHexadecimalDigit(lexer.peekCharacter()) {
+				return "", false
+			}
+
+			lexer.readCharacter()
+			digits.WriteRune(lexer.character)
+		}
+	}
+
+	codePoint, conversionError := strconv.ParseUint(digits.String(), 16, 32)
+	if conversionError != nil || !utf8.ValidRune(rune(codePoint)) {
+		return "", false
+	}
+
+	return string(rune(codePoint)), true
+}
+
+func (lexer *Lexer) readComment(nextToken token.Token) token.Token {
+	var builder strings.Builder
+
+	isBlockComment := lexer.peekCharacter() == '*'
+	nextToken.Type = token.COMMENT
+	firstLineLength := len(lexer.line)
+
+	// Opening characters
+	builder.WriteRune(lexer.character)
+	lexer.readCharacter()
+	builder.WriteRune(lexer.character)
+	lexer.readCharacter()
+
+	for {
+		if lexer.character == 0 {
+			// Block comments have to be closed before the end of the file
+			if isBlockComment {
+				nextToken.Type = token.ILLEGAL
+				nextToken.Reason = "Unterminated block comment"
+			}
+
+			break
+		}
+
+		if !isBlockComment && lexer.character == '\n' {
+			break
+		}
+
+		if isBlockComment && lexer.character == '*' && lexer.peekCharacter() == '/' {
+			builder.WriteString("*/")
+			lexer.readCharacter()
+			lexer.readCharacter()
+			break
+		}
+
+		builder.WriteRune(lexer.character)
+		lexer.readCharacter()
+	}
+
+	nextToken.Literal = builder.String()
+
+	// Like raw strings, comments spanning multiple lines are only covered up to the end of their first line
+	if lexer.lineNumber != nextToken.Line {
+		nextToken.Length = firstLineLength - nextToken.Position
+	}
+
+	if nextToken.Type == token.COMMENT && !lexer.keepComments {
+		return lexer.Next()
+	}
+
+	return nextToken
 }
 
 func (lexer *Lexer) skipWhitespace() {
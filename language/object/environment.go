@@ -6,6 +6,9 @@ type Module (map[string]Object)
 type ProgramEnvironment struct {
 	modules      map[string]Module
 	RunDirectory string
+
+	// Reports integer overflows as errors instead of wrapping around
+	CheckedArithmetic bool
 }
 
 func NewProgramEnvironment(runDirectory string) *ProgramEnvironment {
@@ -66,6 +69,20 @@ func (environment *Environment) Set(name string, value Object) Object {
 	return value
 }
 
+// Updates the variable in the scope that declared it
+func (environment *Environment) Assign(name string, value Object) (Object, bool) {
+	if _, found := environment.store[name]; found {
+		environment.store[name] = value
+		return value, true
+	}
+
+	if environment.outer != nil {
+		return environment.outer.Assign(name, value)
+	}
+
+	return nil, false
+}
+
 func (environment *Environment) Export(name string, value Object) {
 	environment.ProgramEnvironment.RegisterModuleExport(environment.Filepath, name, value)
 }
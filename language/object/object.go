@@ -5,23 +5,31 @@ import (
 	"fmt"
 	"glass/language/ast"
 	"hash/fnv"
+	"math"
+	"sort"
+	"strconv"
 	"strings"
 )
 
 type ObjectType string
 
 const (
-	INTEGER_OBJECT      = "INTEGER"
-	STRING_OBJECT       = "STRING"
-	BOOLEAN_OBJECT      = "BOOLEAN"
-	NULL_OBJECT         = "NULL"
-	RETURN_VALUE_OBJECT = "RETURN_VALUE"
-	ERROR_OBJECT        = "ERROR"
-	ARRAY_OBJECT        = "ARRAY"
-	HASH_OBJECT         = "HASH"
-	FUNCTION_OBJECT     = "FUNCTION"
-	BUILTIN_OBJECT      = "BUILTIN"
-	IMPORT_OBJECT       = "IMPORT"
+	INTEGER_OBJECT       = "INTEGER"
+	FLOAT_OBJECT         = "FLOAT"
+	STRING_OBJECT        = "STRING"
+	BOOLEAN_OBJECT       = "BOOLEAN"
+	NULL_OBJECT          = "NULL"
+	RETURN_VALUE_OBJECT  = "RETURN_VALUE"
+	BREAK_OBJECT         = "BREAK"
+	CONTINUE_OBJECT      = "CONTINUE"
+	SKIPPED_CHAIN_OBJECT = "SKIPPED_CHAIN"
+	ERROR_OBJECT         = "ERROR"
+	EXCEPTION_OBJECT     = "EXCEPTION"
+	ARRAY_OBJECT         = "ARRAY"
+	HASH_OBJECT          = "HASH"
+	FUNCTION_OBJECT      = "FUNCTION"
+	BUILTIN_OBJECT       = "BUILTIN"
+	IMPORT_OBJECT        = "IMPORT"
 )
 
 type Object interface {
@@ -29,13 +37,49 @@ type Object interface {
 	Inspect() string
 }
 
-// Error
+// Error kinds, exposed to scripts as error.kind
+const (
+	GENERIC_ERROR    = "Error"
+	TYPE_ERROR       = "TypeError"
+	REFERENCE_ERROR  = "ReferenceError"
+	RANGE_ERROR      = "RangeError"
+	VALUE_ERROR      = "ValueError"
+	ARITHMETIC_ERROR = "ArithmeticError"
+	ARGUMENT_ERROR   = "ArgumentError"
+	SYNTAX_ERROR     = "SyntaxError"
+)
+
+// Error, unwinding the evaluation until it is caught
 type Error struct {
-	Message string
+	Kind     string
+	Message  string
+	Value    Object // Thrown value, nil for errors raised by the evaluator
+	File     string
+	Line     int
+	Position int
+	Stack    []StackFrame // Function calls the error propagated through, innermost first
 }
 
 func (e *Error) GetType() ObjectType { return ERROR_OBJECT }
-func (e *Error) Inspect() string     { return "ERROR: " + e.Message }
+func (e *Error) Inspect() string     { return "ERROR: " + e.Kind + ": " + e.Message }
+
+// Stack frame, a function call an error propagated through
+type StackFrame struct {
+	Function string
+	File     string
+	Line     int
+	Position int
+}
+
+// Exception, a caught error that is a regular value
+type Exception struct {
+	Error *Error
+}
+
+func (exception *Exception) GetType() ObjectType { return EXCEPTION_OBJECT }
+func (exception *Exception) Inspect() string {
+	return exception.Error.Kind + ": " + exception.Error.Message
+}
 
 // Integer
 type Integer struct {
@@ -45,6 +89,23 @@ type Integer struct {
 func (integer *Integer) GetType() ObjectType { return INTEGER_OBJECT }
 func (integer *Integer) Inspect() string     { return fmt.Sprintf("%d", integer.Value) }
 
+// Float
+type Float struct {
+	Value float64
+}
+
+func (float *Float) GetType() ObjectType { return FLOAT_OBJECT }
+func (float *Float) Inspect() string {
+	inspected := strconv.FormatFloat(float.Value, 'g', -1, 64)
+
+	// Keeping whole floats distinguishable from integers
+	if !strings.ContainsAny(inspected, ".eIN") {
+		inspected += ".0"
+	}
+
+	return inspected
+}
+
 // String
 type String struct {
 	Value string
@@ -75,9 +136,32 @@ type ReturnValue struct {
 func (value *ReturnValue) GetType() ObjectType { return RETURN_VALUE_OBJECT }
 func (value *ReturnValue) Inspect() string     { return value.Value.Inspect() }
 
+// Break
+type Break struct {
+	Label string
+}
+
+func (signal *Break) GetType() ObjectType { return BREAK_OBJECT }
+func (signal *Break) Inspect() string     { return "break" }
+
+// Continue
+type Continue struct {
+	Label string
+}
+
+func (signal *Continue) GetType() ObjectType { return CONTINUE_OBJECT }
+func (signal *Continue) Inspect() string     { return "continue" }
+
+// Skipped chain, passed along member, index and call links once an optional link sees null
+type SkippedChain struct{}
+
+func (signal *SkippedChain) GetType() ObjectType { return SKIPPED_CHAIN_OBJECT }
+func (signal *SkippedChain) Inspect() string     { return "null" }
+
 // Functions
 type Function struct {
-	Parameters  []*ast.Identifier
+	Name        string // Name of the variable first bound to the function, empty if anonymous
+	Parameters  []*ast.Parameter
 	Body        *ast.BlockStatement
 	Environment *Environment
 }
@@ -159,6 +243,18 @@ func (integer *Integer) HashKey() HashKey {
 	}
 }
 
+// Whole floats share the key of the equal integer, as 1 == 1.0
+func (float *Float) HashKey() HashKey {
+	if float.Value == math.Trunc(float.Value) && float.Value >= math.MinInt64 && float.Value < math.MaxInt64 {
+		return (&Integer{Value: int64(float.Value)}).HashKey()
+	}
+
+	return HashKey{
+		Type:  float.GetType(),
+		Value: math.Float64bits(float.Value),
+	}
+}
+
 func (str *String) HashKey() HashKey {
 	hash := fnv.New64a()
 	hash.Write([]byte(str.Value))
@@ -179,6 +275,69 @@ type Hash struct {
 }
 
 func (hash *Hash) GetType() ObjectType { return HASH_OBJECT }
+
+// Keys in a stable order, booleans first, then numbers and strings, each by value
+func (hash *Hash) SortedKeys() []Object {
+	keys := make([]Object, 0, len(hash.Pairs))
+	for _, pair := range hash.Pairs {
+		keys = append(keys, pair.Key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return isKeyBefore(keys[i], keys[j])
+	})
+
+	return keys
+}
+
+func isKeyBefore(left Object, right Object) bool {
+	leftRank, rightRank := getKeyRank(left), getKeyRank(right)
+	if leftRank != rightRank {
+		return leftRank < rightRank
+	}
+
+	switch left := left.(type) {
+
+	case *Boolean:
+		return !left.Value && right.(*Boolean).Value
+
+	case *String:
+		return left.Value < right.(*String).Value
+
+	}
+
+	// Integers are compared exactly, and mixed numbers as floats
+	leftInteger, leftOk := left.(*Integer)
+	rightInteger, rightOk := right.(*Integer)
+	if leftOk && rightOk {
+		return leftInteger.Value < rightInteger.Value
+	}
+
+	return getKeyNumber(left) < getKeyNumber(right)
+}
+
+func getKeyRank(key Object) int {
+	switch key.(type) {
+
+	case *Boolean:
+		return 0
+
+	case *Integer, *Float:
+		return 1
+
+	default:
+		return 2
+
+	}
+}
+
+func getKeyNumber(key Object) float64 {
+	if integer, ok := key.(*Integer); ok {
+		return float64(integer.Value)
+	}
+
+	return key.(*Float).Value
+}
 func (hash *Hash) Inspect() string {
 	var buffer bytes.Buffer
 	pairs := []string{}
@@ -2,27 +2,24 @@ package parser
 
 import (
 	"bufio"
-	"fmt"
 	"glass/language/ast"
+	"glass/language/diagnostic"
 	"glass/language/lexer"
-	"log"
 	"os"
 )
 
-func GetParsedFile(filepath string) *ast.Program {
+// Parses a whole file, returning its syntax errors as diagnostics
+func GetParsedFile(filepath string) (*ast.Program, []*diagnostic.Diagnostic, error) {
 	// File handling
 	file, openError := os.Open(filepath)
 	if openError != nil {
-		fmt.Println("Error reading file:", openError)
-		return nil
+		return nil, nil, openError
 	}
 
 	defer file.Close()
 
 	scanner := bufio.NewScanner(file)
-	if !scanner.Scan() {
-		fmt.Println("File is empty.")
-	}
+	scanner.Scan()
 
 	firstLine := scanner.Text()
 
@@ -36,15 +33,12 @@ func GetParsedFile(filepath string) *ast.Program {
 	})
 
 	parser := New(lexer)
+	parser.Filepath = filepath
 	program := parser.ParseProgram()
 
-	errors := parser.GetErrors()
-	if len(errors) > 0 {
-		for _, err := range errors {
-			log.Print(err)
-		}
-		return nil
+	if scanError := scanner.Err(); scanError != nil {
+		return nil, nil, scanError
 	}
 
-	return program
+	return program, parser.GetDiagnostics(), nil
 }
@@ -1,38 +1,70 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
 	ast "glass/language/ast"
+	diagnostic "glass/language/diagnostic"
 	lexer "glass/language/lexer"
 	token "glass/language/token"
 	"strconv"
+	"strings"
 )
 
 const (
 	_ int = iota
 	LOWEST
+	ASSIGN      // = or +=
+	CONDITIONAL // condition ? consequence : alternative
+	NULLISH     // ??
+	OR          // ||
+	AND         // &&
+	BITWISE_OR  // |
+	BITWISE_XOR // ^
+	BITWISE_AND // &
 	EQUALS      // ==
 	LESSGREATER // > or <
+	SHIFT       // << or >>
 	SUM         // +
 	PRODUCT     // *
-	PREFIX      // -expression or !expression
+	PREFIX      // -expression, !expression or ~expression
+	POWER       // **
 	CALL        // myFunction(expression, expression)
 	INDEX       // array[index]
-	ACCESS      // imported.value
+	ACCESS      // imported.value or hash?.key
 )
 
 var precedences = map[token.TokenType]int{
-	token.EQUALS:       EQUALS,
-	token.NOT_EQUALS:   EQUALS,
-	token.LESS_THAN:    LESSGREATER,
-	token.GREATER_THAN: LESSGREATER,
-	token.PLUS:         SUM,
-	token.MINUS:        SUM,
-	token.SLASH:        PRODUCT,
-	token.ASTERISK:     PRODUCT,
-	token.LPAREN:       CALL,
-	token.LBRACKET:     INDEX,
-	token.DOT:          ACCESS,
+	token.ASSIGN:          ASSIGN,
+	token.PLUS_ASSIGN:     ASSIGN,
+	token.MINUS_ASSIGN:    ASSIGN,
+	token.ASTERISK_ASSIGN: ASSIGN,
+	token.SLASH_ASSIGN:    ASSIGN,
+	token.QUESTION:        CONDITIONAL,
+	token.NULLISH:         NULLISH,
+	token.OR:              OR,
+	token.AND:             AND,
+	token.PIPE:            BITWISE_OR,
+	token.CARET:           BITWISE_XOR,
+	token.AMPERSAND:       BITWISE_AND,
+	token.SHIFT_LEFT:      SHIFT,
+	token.SHIFT_RIGHT:     SHIFT,
+	token.EQUALS:          EQUALS,
+	token.NOT_EQUALS:      EQUALS,
+	token.LESS_THAN:       LESSGREATER,
+	token.GREATER_THAN:    LESSGREATER,
+	token.LESS_EQUALS:     LESSGREATER,
+	token.GREATER_EQUALS:  LESSGREATER,
+	token.PLUS:            SUM,
+	token.MINUS:           SUM,
+	token.SLASH:           PRODUCT,
+	token.ASTERISK:        PRODUCT,
+	token.PERCENT:         PRODUCT,
+	token.POWER:           POWER,
+	token.LPAREN:          CALL,
+	token.LBRACKET:        INDEX,
+	token.DOT:             ACCESS,
+	token.QUESTION_DOT:    ACCESS,
 }
 
 type (
@@ -44,7 +76,19 @@ type Parser struct {
 	lexer        *lexer.Lexer
 	currentToken token.Token
 	peekToken    token.Token
-	errors       []string
+	diagnostics  []*diagnostic.Diagnostic
+
+	// File being parsed, reported in diagnostics
+	Filepath string
+
+	// Labels of the loops being parsed, unlabeled loops are stored as ""
+	loopLabels []string
+
+	// Braces opened before the current token, used to resynchronize after errors
+	braceDepth int
+
+	// Set by the first error of a statement, silencing the errors it causes
+	panicking bool
 
 	prefixParsingFunctions map[token.TokenType]prefixParsingFunction
 	infixParsingFunctions  map[token.TokenType]infixParsingFunction
@@ -52,22 +96,28 @@ type Parser struct {
 
 func New(lexer *lexer.Lexer) *Parser {
 	parser := &Parser{
-		lexer:  lexer,
-		errors: []string{},
+		lexer:       lexer,
+		diagnostics: []*diagnostic.Diagnostic{},
 	}
 
 	// Registering prefixes
 	parser.prefixParsingFunctions = make(map[token.TokenType]prefixParsingFunction)
 	parser.registerPrefix(token.IDENTIFIER, parser.parseIdentifier)
 	parser.registerPrefix(token.INT, parser.parseIntegerLiteral)
+	parser.registerPrefix(token.FLOAT, parser.parseFloatLiteral)
 	parser.registerPrefix(token.NOT, parser.parsePrefixExpression)
 	parser.registerPrefix(token.MINUS, parser.parsePrefixExpression)
+	parser.registerPrefix(token.TILDE, parser.parsePrefixExpression)
 	parser.registerPrefix(token.TRUE, parser.parseBoolean)
 	parser.registerPrefix(token.FALSE, parser.parseBoolean)
+	parser.registerPrefix(token.NULL, parser.parseNullLiteral)
 	parser.registerPrefix(token.LPAREN, parser.parseGroupedExpression)
 	parser.registerPrefix(token.IF, parser.parseIfExpression)
+	parser.registerPrefix(token.MATCH, parser.parseMatchExpression)
+	parser.registerPrefix(token.ELLIPSIS, parser.parseSpreadExpression)
 	parser.registerPrefix(token.FUNCTION, parser.parseFunction)
 	parser.registerPrefix(token.STRING, parser.parseStringLiteral)
+	parser.registerPrefix(token.TEMPLATE_HEAD, parser.parseTemplateLiteral)
 	parser.registerPrefix(token.LBRACKET, parser.parseArrayLiteral)
 	parser.registerPrefix(token.LBRACE, parser.parseHashLiteral)
 
@@ -81,9 +131,28 @@ func New(lexer *lexer.Lexer) *Parser {
 	parser.registerInfix(token.MINUS, parser.parseInfixExpression)
 	parser.registerInfix(token.SLASH, parser.parseInfixExpression)
 	parser.registerInfix(token.ASTERISK, parser.parseInfixExpression)
+	parser.registerInfix(token.LESS_EQUALS, parser.parseInfixExpression)
+	parser.registerInfix(token.GREATER_EQUALS, parser.parseInfixExpression)
+	parser.registerInfix(token.PERCENT, parser.parseInfixExpression)
+	parser.registerInfix(token.POWER, parser.parseInfixExpression)
+	parser.registerInfix(token.AMPERSAND, parser.parseInfixExpression)
+	parser.registerInfix(token.PIPE, parser.parseInfixExpression)
+	parser.registerInfix(token.CARET, parser.parseInfixExpression)
+	parser.registerInfix(token.SHIFT_LEFT, parser.parseInfixExpression)
+	parser.registerInfix(token.SHIFT_RIGHT, parser.parseInfixExpression)
 	parser.registerInfix(token.LPAREN, parser.parseCallExpression)
 	parser.registerInfix(token.LBRACKET, parser.parseIndexExpression)
 	parser.registerInfix(token.DOT, parser.parseAccessExpression)
+	parser.registerInfix(token.QUESTION_DOT, parser.parseOptionalChainExpression)
+	parser.registerInfix(token.AND, parser.parseLogicalExpression)
+	parser.registerInfix(token.OR, parser.parseLogicalExpression)
+	parser.registerInfix(token.NULLISH, parser.parseLogicalExpression)
+	parser.registerInfix(token.QUESTION, parser.parseConditionalExpression)
+	parser.registerInfix(token.ASSIGN, parser.parseAssignmentExpression)
+	parser.registerInfix(token.PLUS_ASSIGN, parser.parseAssignmentExpression)
+	parser.registerInfix(token.MINUS_ASSIGN, parser.parseAssignmentExpression)
+	parser.registerInfix(token.ASTERISK_ASSIGN, parser.parseAssignmentExpression)
+	parser.registerInfix(token.SLASH_ASSIGN, parser.parseAssignmentExpression)
 
 	// Read two tokens, so currentToken and peekToken are both set
 	parser.nextToken()
@@ -92,8 +161,25 @@ func New(lexer *lexer.Lexer) *Parser {
 }
 
 func (parser *Parser) nextToken() {
+	switch parser.currentToken.Type {
+
+	case token.LBRACE:
+		parser.braceDepth++
+
+	case token.RBRACE:
+		if parser.braceDepth > 0 {
+			parser.braceDepth--
+		}
+
+	}
+
 	parser.currentToken = parser.peekToken
 	parser.peekToken = parser.lexer.Next()
+
+	// Comments can be kept by the lexer, but have no meaning to the parser
+	for parser.peekToken.Type == token.COMMENT {
+		parser.peekToken = parser.lexer.Next()
+	}
 }
 
 func (parser *Parser) registerPrefix(tokenType token.TokenType, function prefixParsingFunction) {
@@ -109,8 +195,14 @@ func (parser *Parser) ParseProgram() *ast.Program {
 	}
 
 	for parser.currentToken.Type != token.EOF {
+		start, depth := parser.currentToken, parser.braceDepth
 		statement := parser.parseStatement()
 
+		if parser.panicking {
+			parser.synchronize(start, depth)
+			continue
+		}
+
 		if statement != nil {
 			program.Statements = append(program.Statements, statement)
 		}
@@ -121,6 +213,55 @@ func (parser *Parser) ParseProgram() *ast.Program {
 	return program
 }
 
+// Statement keywords, where parsing can resume after an error
+var synchronizingTokens = map[token.TokenType]bool{
+	token.LET:      true,
+	token.RETURN:   true,
+	token.IMPORT:   true,
+	token.EXPORT:   true,
+	token.WHILE:    true,
+	token.FOR:      true,
+	token.BREAK:    true,
+	token.CONTINUE: true,
+	token.TRY:      true,
+	token.THROW:    true,
+}
+
+// Skips the rest of a statement that failed to parse, stopping after its semicolon,
+// at the next statement keyword, or at the brace closing the enclosing block
+func (parser *Parser) synchronize(start token.Token, depth int) {
+	parser.panicking = false
+
+	// Always making progress, even when the statement failed on its first token
+	if parser.currentToken == start {
+		parser.nextToken()
+	}
+
+	for !parser.isCurrentToken(token.EOF) {
+
+		// The failed statement already consumed the brace closing the enclosing block
+		if parser.braceDepth < depth {
+			return
+		}
+
+		// Boundaries nested in braces opened by the failed statement are skipped
+		if parser.braceDepth == depth {
+			switch {
+
+			case parser.isCurrentToken(token.SEMICOLON):
+				parser.nextToken()
+				return
+
+			case parser.isCurrentToken(token.RBRACE), synchronizingTokens[parser.currentToken.Type]:
+				return
+
+			}
+		}
+
+		parser.nextToken()
+	}
+}
+
 // Statements
 
 func (parser *Parser) parseStatement() ast.Statement {
@@ -138,24 +279,53 @@ func (parser *Parser) parseStatement() ast.Statement {
 	case token.EXPORT:
 		return parser.parseExportStatement()
 
+	case token.WHILE:
+		return parser.parseWhileStatement(nil)
+
+	case token.FOR:
+		return parser.parseForStatement(nil)
+
+	case token.BREAK, token.CONTINUE:
+		return parser.parseLoopControlStatement()
+
+	case token.TRY:
+		return parser.parseTryStatement()
+
+	case token.THROW:
+		return parser.parseThrowStatement()
+
 	default:
+		if parser.isCurrentToken(token.IDENTIFIER) && parser.isPeekToken(token.COLON) {
+			return parser.parseLabeledStatement()
+		}
+
 		return parser.parseExpressionStatement()
 
 	}
 }
 
 func (parser *Parser) parseLetStatement() *ast.LetStatement {
-	statement := &ast.LetStatement{
-		Token: parser.currentToken,
+	statement := parser.parseLetBinding()
+	if statement == nil {
+		return nil
 	}
 
-	if !parser.expectPeek(token.IDENTIFIER) {
-		return nil
+	for parser.isPeekToken(token.SEMICOLON) {
+		parser.nextToken()
 	}
 
-	statement.Identifier = &ast.Identifier{
+	return statement
+}
+
+// Parses "let pattern = expression", leaving the semicolons that follow to the caller
+func (parser *Parser) parseLetBinding() *ast.LetStatement {
+	statement := &ast.LetStatement{
 		Token: parser.currentToken,
-		Value: parser.currentToken.Literal,
+	}
+
+	statement.Pattern = parser.parseBindingPattern()
+	if statement.Pattern == nil {
+		return nil
 	}
 
 	if !parser.expectPeek(token.ASSIGN) {
@@ -166,10 +336,6 @@ func (parser *Parser) parseLetStatement() *ast.LetStatement {
 
 	statement.Expression = parser.parseExpression(LOWEST)
 
-	for parser.isPeekToken(token.SEMICOLON) {
-		parser.nextToken()
-	}
-
 	return statement
 }
 
@@ -237,156 +403,159 @@ func (parser *Parser) parseExportStatement() *ast.ExportStatement {
 	return statement
 }
 
-func (parser *Parser) parseBlockStatement() *ast.BlockStatement {
-	blockStatement := &ast.BlockStatement{
-		Token:      parser.currentToken,
-		Statements: []ast.Statement{},
+func (parser *Parser) parseThrowStatement() *ast.ThrowStatement {
+	statement := &ast.ThrowStatement{
+		Token: parser.currentToken,
 	}
 
 	parser.nextToken()
-	for !parser.isCurrentToken(token.RBRACE) && !parser.isCurrentToken(token.EOF) {
-		statement := parser.parseStatement()
-		if statement != nil {
-			blockStatement.Statements = append(blockStatement.Statements, statement)
-		}
 
+	statement.Expression = parser.parseExpression(LOWEST)
+	if statement.Expression == nil {
+		return nil
+	}
+
+	for parser.isPeekToken(token.SEMICOLON) {
 		parser.nextToken()
 	}
 
-	return blockStatement
+	return statement
 }
 
-func (parser *Parser) parseExpressionStatement() *ast.ExpressionStatement {
-	statement := &ast.ExpressionStatement{}
+func (parser *Parser) parseTryStatement() *ast.TryStatement {
+	statement := &ast.TryStatement{
+		Token: parser.currentToken,
+	}
 
-	statement.Expression = parser.parseExpression(LOWEST)
+	if !parser.expectPeek(token.LBRACE) {
+		return nil
+	}
 
-	if parser.isPeekToken(token.SEMICOLON) {
+	statement.Body = parser.parseBlockStatement()
+
+	if parser.isPeekToken(token.CATCH) {
 		parser.nextToken()
-	}
 
-	return statement
-}
+		if parser.isPeekToken(token.LPAREN) {
+			parser.nextToken()
 
-// Expressions
+			if !parser.expectPeek(token.IDENTIFIER) {
+				return nil
+			}
 
-func (parser *Parser) parseExpression(precedence int) ast.Expression {
-	prefix := parser.prefixParsingFunctions[parser.currentToken.Type]
+			statement.CatchParameter = &ast.Identifier{
+				Token: parser.currentToken,
+				Value: parser.currentToken.Literal,
+			}
 
-	if prefix == nil {
-		if parser.currentToken.Type == token.ILLEGAL {
-			parser.addIllegalTokenError(parser.currentToken)
+			if !parser.expectPeek(token.RPAREN) {
+				return nil
+			}
+		}
+
+		if !parser.expectPeek(token.LBRACE) {
 			return nil
 		}
 
-		message := fmt.Sprintf("no prefix parse function found for %q token", parser.currentToken.Type)
-		parser.errors = append(parser.errors, message)
-		return nil
+		statement.Catch = parser.parseBlockStatement()
 	}
 
-	leftExpression := prefix()
+	if parser.isPeekToken(token.FINALLY) {
+		parser.nextToken()
 
-	for !parser.isPeekToken(token.SEMICOLON) && precedence < parser.getPeekPrecedence() {
-		infix := parser.infixParsingFunctions[parser.peekToken.Type]
-		if infix == nil {
-			return leftExpression
+		if !parser.expectPeek(token.LBRACE) {
+			return nil
 		}
 
-		parser.nextToken()
-
-		leftExpression = infix(leftExpression)
+		statement.Finally = parser.parseBlockStatement()
 	}
 
-	return leftExpression
-}
+	if statement.Catch == nil && statement.Finally == nil {
+		parser.addError(diagnostic.INVALID_STATEMENT, statement.Token, "try statement without catch or finally")
+		return nil
+	}
 
-func (parser *Parser) parseIdentifier() ast.Expression {
-	return &ast.Identifier{
-		Token: parser.currentToken,
-		Value: parser.currentToken.Literal,
+	if parser.isPeekToken(token.SEMICOLON) {
+		parser.nextToken()
 	}
+
+	return statement
 }
 
-func (parser *Parser) parseIntegerLiteral() ast.Expression {
-	literal := &ast.IntegerLiteral{
+func (parser *Parser) parseWhileStatement(label *ast.Identifier) *ast.WhileStatement {
+	statement := &ast.WhileStatement{
 		Token: parser.currentToken,
+		Label: label,
 	}
 
-	value, conversionError := strconv.ParseInt(parser.currentToken.Literal, 0, 64)
-	if conversionError != nil {
-		message := fmt.Sprintf("could not parse %q as integer", parser.currentToken.Literal)
-		parser.errors = append(parser.errors, message)
+	if !parser.expectPeek(token.LPAREN) {
 		return nil
 	}
 
-	literal.Value = value
-	return literal
-}
-
-func (parser *Parser) parseStringLiteral() ast.Expression {
-	return &ast.StringLiteral{
-		Token: parser.currentToken,
-		Value: parser.currentToken.Literal,
-	}
-}
+	parser.nextToken()
+	statement.Condition = parser.parseExpression(LOWEST)
 
-func (parser *Parser) parseBoolean() ast.Expression {
-	return &ast.Boolean{
-		Token: parser.currentToken,
-		Value: parser.isCurrentToken(token.TRUE),
+	if !parser.expectPeek(token.RPAREN) {
+		return nil
 	}
-}
 
-func (parser *Parser) parsePrefixExpression() ast.Expression {
-	expression := &ast.PrefixExpression{
-		Token:    parser.currentToken,
-		Operator: parser.currentToken.Literal,
+	if !parser.expectPeek(token.LBRACE) {
+		return nil
 	}
 
-	parser.nextToken()
+	statement.Body = parser.parseLoopBody(label)
 
-	expression.Expression = parser.parseExpression(PREFIX)
+	if parser.isPeekToken(token.SEMICOLON) {
+		parser.nextToken()
+	}
 
-	return expression
+	return statement
 }
 
-func (parser *Parser) parseInfixExpression(leftExpression ast.Expression) ast.Expression {
-	expression := &ast.InfixExpression{
-		Token:          parser.currentToken,
-		LeftExpression: leftExpression,
-		Operator:       parser.currentToken.Literal,
+func (parser *Parser) parseForStatement(label *ast.Identifier) ast.Statement {
+	forToken := parser.currentToken
+
+	if !parser.expectPeek(token.LPAREN) {
+		return nil
 	}
 
-	precedence := parser.getCurrentPrecedence()
 	parser.nextToken()
-	expression.RightExpression = parser.parseExpression(precedence)
 
-	return expression
-}
+	if parser.isCurrentToken(token.IDENTIFIER) && parser.isPeekToken(token.IN) {
+		return parser.parseForInStatement(forToken, label)
+	}
 
-func (parser *Parser) parseGroupedExpression() ast.Expression {
-	parser.nextToken()
+	statement := &ast.ForStatement{
+		Token: forToken,
+		Label: label,
+	}
 
-	expression := parser.parseExpression(LOWEST)
+	// Initializations end at a single semicolon, an empty condition following it
+	if !parser.isCurrentToken(token.SEMICOLON) {
+		if parser.isCurrentToken(token.LET) {
+			statement.Initialization = parser.parseLetBinding()
+		} else {
+			statement.Initialization = parser.parseStatement()
+		}
 
-	if !parser.expectPeek(token.RPAREN) {
-		return nil
+		if !parser.isCurrentToken(token.SEMICOLON) && !parser.expectPeek(token.SEMICOLON) {
+			return nil
+		}
 	}
 
-	return expression
-}
-
-func (parser *Parser) parseIfExpression() ast.Expression {
-	expression := &ast.IfExpression{
-		Token: parser.currentToken,
+	if !parser.isPeekToken(token.SEMICOLON) {
+		parser.nextToken()
+		statement.Condition = parser.parseExpression(LOWEST)
 	}
 
-	if !parser.expectPeek(token.LPAREN) {
+	if !parser.expectPeek(token.SEMICOLON) {
 		return nil
 	}
 
-	parser.nextToken()
-	expression.Condition = parser.parseExpression(LOWEST)
+	if !parser.isPeekToken(token.RPAREN) {
+		parser.nextToken()
+		statement.Update = parser.parseStatement()
+	}
 
 	if !parser.expectPeek(token.RPAREN) {
 		return nil
@@ -396,80 +565,902 @@ func (parser *Parser) parseIfExpression() ast.Expression {
 		return nil
 	}
 
-	expression.Consequence = parser.parseBlockStatement()
-
-	if !parser.isPeekToken(token.ELSE) {
-		return expression
-	}
+	statement.Body = parser.parseLoopBody(label)
 
-	// Parsing else expression
-	parser.nextToken()
-	if !parser.expectPeek(token.LBRACE) {
-		return nil
+	if parser.isPeekToken(token.SEMICOLON) {
+		parser.nextToken()
 	}
 
-	expression.Alternative = parser.parseBlockStatement()
-	return expression
+	return statement
 }
 
-func (parser *Parser) parseFunction() ast.Expression {
-	function := &ast.Function{
-		Token: parser.currentToken,
+func (parser *Parser) parseForInStatement(forToken token.Token, label *ast.Identifier) ast.Statement {
+	statement := &ast.ForInStatement{
+		Token: forToken,
+		Label: label,
+		Identifier: &ast.Identifier{
+			Token: parser.currentToken,
+			Value: parser.currentToken.Literal,
+		},
 	}
 
-	if !parser.expectPeek(token.LPAREN) {
+	// Skipping the in keyword
+	parser.nextToken()
+	parser.nextToken()
+
+	statement.Iterable = parser.parseExpression(LOWEST)
+
+	if !parser.expectPeek(token.RPAREN) {
 		return nil
 	}
 
-	function.Parameters = parser.parseFunctionParameters()
-
 	if !parser.expectPeek(token.LBRACE) {
 		return nil
 	}
 
-	function.Body = parser.parseBlockStatement()
-	return function
-}
-
-func (parser *Parser) parseFunctionParameters() []*ast.Identifier {
-	parameters := []*ast.Identifier{}
+	statement.Body = parser.parseLoopBody(label)
 
-	if parser.isPeekToken(token.RPAREN) {
+	if parser.isPeekToken(token.SEMICOLON) {
 		parser.nextToken()
-		return parameters
 	}
 
-	parser.nextToken()
+	return statement
+}
 
-	identifier := &ast.Identifier{
+func (parser *Parser) parseLoopBody(label *ast.Identifier) *ast.BlockStatement {
+	labelValue := ""
+	if label != nil {
+		labelValue = label.Value
+	}
+
+	parser.loopLabels = append(parser.loopLabels, labelValue)
+	body := parser.parseBlockStatement()
+	parser.loopLabels = parser.loopLabels[:len(parser.loopLabels)-1]
+
+	return body
+}
+
+func (parser *Parser) parseLabeledStatement() ast.Statement {
+	label := &ast.Identifier{
 		Token: parser.currentToken,
 		Value: parser.currentToken.Literal,
 	}
-	parameters = append(parameters, identifier)
 
-	for parser.isPeekToken(token.COMMA) {
-		parser.nextToken()
-		parser.nextToken()
+	parser.nextToken()
+	parser.nextToken()
 
-		identifier := &ast.Identifier{
-			Token: parser.currentToken,
-			Value: parser.currentToken.Literal,
-		}
-		parameters = append(parameters, identifier)
-	}
+	switch parser.currentToken.Type {
 
-	if !parser.expectPeek(token.RPAREN) {
+	case token.WHILE:
+		return parser.parseWhileStatement(label)
+
+	case token.FOR:
+		return parser.parseForStatement(label)
+
+	default:
+		parser.addError(diagnostic.INVALID_STATEMENT, label.Token, "Label %q must be followed by a loop", label.Value)
 		return nil
-	}
 
-	return parameters
+	}
 }
 
-func (parser *Parser) parseCallExpression(function ast.Expression) ast.Expression {
-	expression := &ast.CallExpression{
+func (parser *Parser) parseLoopControlStatement() ast.Statement {
+	controlToken := parser.currentToken
+
+	var label *ast.Identifier
+	if parser.isPeekToken(token.IDENTIFIER) {
+		parser.nextToken()
+		label = &ast.Identifier{
+			Token: parser.currentToken,
+			Value: parser.currentToken.Literal,
+		}
+	}
+
+	for parser.isPeekToken(token.SEMICOLON) {
+		parser.nextToken()
+	}
+
+	if len(parser.loopLabels) == 0 {
+		parser.addError(diagnostic.INVALID_STATEMENT, controlToken, "%s statement outside of a loop", controlToken.Literal)
+		return nil
+	}
+
+	if label != nil && !parser.isLoopLabel(label.Value) {
+		parser.addError(diagnostic.INVALID_STATEMENT, label.Token, "Unknown loop label %q", label.Value)
+		return nil
+	}
+
+	if controlToken.Type == token.BREAK {
+		return &ast.BreakStatement{Token: controlToken, Label: label}
+	}
+
+	return &ast.ContinueStatement{Token: controlToken, Label: label}
+}
+
+func (parser *Parser) parseBlockStatement() *ast.BlockStatement {
+	blockStatement := &ast.BlockStatement{
+		Token:      parser.currentToken,
+		Statements: []ast.Statement{},
+	}
+
+	parser.nextToken()
+	for !parser.isCurrentToken(token.RBRACE) && !parser.isCurrentToken(token.EOF) {
+		start, depth := parser.currentToken, parser.braceDepth
+		statement := parser.parseStatement()
+
+		if parser.panicking {
+			parser.synchronize(start, depth)
+			if parser.braceDepth < depth {
+				break
+			}
+
+			continue
+		}
+
+		if statement != nil {
+			blockStatement.Statements = append(blockStatement.Statements, statement)
+		}
+
+		parser.nextToken()
+	}
+
+	return blockStatement
+}
+
+func (parser *Parser) parseExpressionStatement() *ast.ExpressionStatement {
+	statement := &ast.ExpressionStatement{}
+
+	statement.Expression = parser.parseExpression(LOWEST)
+
+	if parser.isPeekToken(token.SEMICOLON) {
+		parser.nextToken()
+	}
+
+	return statement
+}
+
+// Expressions
+
+func (parser *Parser) parseExpression(precedence int) ast.Expression {
+	prefix := parser.prefixParsingFunctions[parser.currentToken.Type]
+
+	if prefix == nil {
+		if parser.currentToken.Type == token.ILLEGAL {
+			parser.addIllegalTokenError(parser.currentToken)
+			return nil
+		}
+
+		parser.addError(
+			diagnostic.MISSING_EXPRESSION,
+			parser.currentToken,
+			"no prefix parse function found for %q token",
+			parser.currentToken.Type,
+		)
+		return nil
+	}
+
+	leftExpression := prefix()
+
+	for !parser.isPeekToken(token.SEMICOLON) && precedence < parser.getPeekPrecedence() {
+		infix := parser.infixParsingFunctions[parser.peekToken.Type]
+		if infix == nil {
+			return leftExpression
+		}
+
+		parser.nextToken()
+
+		leftExpression = infix(leftExpression)
+	}
+
+	return leftExpression
+}
+
+func (parser *Parser) parseIdentifier() ast.Expression {
+	return &ast.Identifier{
+		Token: parser.currentToken,
+		Value: parser.currentToken.Literal,
+	}
+}
+
+func (parser *Parser) parseIntegerLiteral() ast.Expression {
+	literal := &ast.IntegerLiteral{
+		Token: parser.currentToken,
+	}
+
+	value, conversionError := parseIntegerValue(parser.currentToken.Literal)
+	if conversionError != nil {
+		format := "could not parse %q as integer"
+		if errors.Is(conversionError, strconv.ErrRange) {
+			format = "integer literal %s out of range"
+		}
+
+		parser.addError(diagnostic.INVALID_LITERAL, parser.currentToken, format, parser.currentToken.Literal)
+		return nil
+	}
+
+	literal.Value = value
+	return literal
+}
+
+// Literals are decimal unless they start with a base prefix, even with leading zeros
+func parseIntegerValue(literal string) (int64, error) {
+	// Base prefixes and their digit separators are handled by ParseInt
+	if len(literal) > 1 && literal[0] == '0' && strings.ContainsRune("xXbBoO", rune(literal[1])) {
+		return strconv.ParseInt(literal, 0, 64)
+	}
+
+	// Underscores can only separate digits
+	if strings.HasSuffix(literal, "_") || strings.Contains(literal, "__") {
+		return 0, strconv.ErrSyntax
+	}
+
+	return strconv.ParseInt(strings.ReplaceAll(literal, "_", ""), 10, 64)
+}
+
+func (parser *Parser) parseFloatLiteral() ast.Expression {
+	literal := &ast.FloatLiteral{
+		Token: parser.currentToken,
+	}
+
+	value, conversionError := strconv.ParseFloat(parser.currentToken.Literal, 64)
+	if conversionError != nil {
+		format := "could not parse %q as float"
+		if errors.Is(conversionError, strconv.ErrRange) {
+			format = "float literal %s out of range"
+		}
+
+		parser.addError(diagnostic.INVALID_LITERAL, parser.currentToken, format, parser.currentToken.Literal)
+		return nil
+	}
+
+	literal.Value = value
+	return literal
+}
+
+func (parser *Parser) parseStringLiteral() ast.Expression {
+	return &ast.StringLiteral{
+		Token: parser.currentToken,
+		Value: parser.currentToken.Literal,
+	}
+}
+
+func (parser *Parser) parseTemplateLiteral() ast.Expression {
+	template := &ast.TemplateLiteral{
+		Token: parser.currentToken,
+		Parts: []ast.Expression{parser.parseStringLiteral()},
+	}
+
+	for {
+		parser.nextToken()
+
+		interpolation := &ast.Interpolation{
+			Token: parser.currentToken,
+		}
+
+		if parser.isCurrentToken(token.TEMPLATE_MIDDLE) || parser.isCurrentToken(token.TEMPLATE_TAIL) {
+			parser.addError(diagnostic.MISSING_EXPRESSION, parser.currentToken, "Empty string interpolation")
+			return nil
+		}
+
+		interpolation.Expression = parser.parseExpression(LOWEST)
+		if interpolation.Expression == nil {
+			return nil
+		}
+
+		template.Parts = append(template.Parts, interpolation)
+
+		// Strings left unterminated after an interpolation
+		if parser.isPeekToken(token.ILLEGAL) {
+			parser.nextToken()
+			parser.addIllegalTokenError(parser.currentToken)
+			return nil
+		}
+
+		if parser.isPeekToken(token.TEMPLATE_MIDDLE) {
+			parser.nextToken()
+			template.Parts = append(template.Parts, parser.parseStringLiteral())
+			continue
+		}
+
+		if !parser.expectPeek(token.TEMPLATE_TAIL) {
+			return nil
+		}
+
+		template.Parts = append(template.Parts, parser.parseStringLiteral())
+		return template
+	}
+}
+
+func (parser *Parser) parseBoolean() ast.Expression {
+	return &ast.Boolean{
+		Token: parser.currentToken,
+		Value: parser.isCurrentToken(token.TRUE),
+	}
+}
+
+func (parser *Parser) parseNullLiteral() ast.Expression {
+	return &ast.NullLiteral{Token: parser.currentToken}
+}
+
+func (parser *Parser) parsePrefixExpression() ast.Expression {
+	expression := &ast.PrefixExpression{
+		Token:    parser.currentToken,
+		Operator: parser.currentToken.Literal,
+	}
+
+	parser.nextToken()
+
+	expression.Expression = parser.parseExpression(PREFIX)
+
+	return expression
+}
+
+func (parser *Parser) parseInfixExpression(leftExpression ast.Expression) ast.Expression {
+	expression := &ast.InfixExpression{
+		Token:          parser.currentToken,
+		LeftExpression: leftExpression,
+		Operator:       parser.currentToken.Literal,
+	}
+
+	precedence := parser.getCurrentPrecedence()
+
+	// Exponentiation is right associative, so 2 ** 3 ** 2 is 2 ** (3 ** 2)
+	if parser.isCurrentToken(token.POWER) {
+		precedence--
+	}
+
+	parser.nextToken()
+	expression.RightExpression = parser.parseExpression(precedence)
+
+	return expression
+}
+
+func (parser *Parser) parseLogicalExpression(leftExpression ast.Expression) ast.Expression {
+	expression := &ast.LogicalExpression{
+		Token:          parser.currentToken,
+		LeftExpression: leftExpression,
+		Operator:       parser.currentToken.Literal,
+	}
+
+	precedence := parser.getCurrentPrecedence()
+	parser.nextToken()
+	expression.RightExpression = parser.parseExpression(precedence)
+
+	return expression
+}
+
+func (parser *Parser) parseConditionalExpression(condition ast.Expression) ast.Expression {
+	expression := &ast.ConditionalExpression{
 		Token:     parser.currentToken,
-		Function:  function,
-		Arguments: parser.parseExpressions(token.RPAREN),
+		Condition: condition,
+	}
+
+	parser.nextToken()
+	expression.Consequence = parser.parseExpression(LOWEST)
+
+	if !parser.expectPeek(token.COLON) {
+		return nil
+	}
+
+	// Conditionals are right-associative, so "a ? b : c ? d : e" nests in the alternative
+	parser.nextToken()
+	expression.Alternative = parser.parseExpression(CONDITIONAL - 1)
+
+	return expression
+}
+
+func (parser *Parser) parseAssignmentExpression(target ast.Expression) ast.Expression {
+	expression := &ast.AssignmentExpression{
+		Token:    parser.currentToken,
+		Target:   target,
+		Operator: parser.currentToken.Literal,
+	}
+
+	// Targets left incomplete by an earlier error cannot be printed
+	if target == nil || parser.panicking {
+		return nil
+	}
+
+	if !isAssignmentTarget(target) {
+		parser.addError(diagnostic.INVALID_ASSIGNMENT, parser.currentToken, "Invalid assignment target %s", target.String())
+		return nil
+	}
+
+	parser.nextToken()
+
+	// Assignments are right associative, so a = b = c assigns c to both
+	expression.Value = parser.parseExpression(LOWEST)
+
+	return expression
+}
+
+func (parser *Parser) parseGroupedExpression() ast.Expression {
+	parser.nextToken()
+
+	expression := parser.parseExpression(LOWEST)
+
+	if !parser.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	return expression
+}
+
+func (parser *Parser) parseIfExpression() ast.Expression {
+	expression := &ast.IfExpression{
+		Token: parser.currentToken,
+	}
+
+	if !parser.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	parser.nextToken()
+	expression.Condition = parser.parseExpression(LOWEST)
+
+	if !parser.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	if !parser.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	expression.Consequence = parser.parseBlockStatement()
+
+	if !parser.isPeekToken(token.ELSE) {
+		return expression
+	}
+
+	// Parsing else expression
+	parser.nextToken()
+	if parser.isPeekToken(token.IF) {
+		parser.nextToken()
+
+		alternative := parser.parseIfExpression()
+		if alternative == nil {
+			return nil
+		}
+
+		expression.Alternative = alternative
+		return expression
+	}
+
+	if !parser.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	expression.Alternative = parser.parseBlockStatement()
+	return expression
+}
+
+func (parser *Parser) parseMatchExpression() ast.Expression {
+	expression := &ast.MatchExpression{
+		Token: parser.currentToken,
+	}
+
+	if !parser.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	parser.nextToken()
+	expression.Subject = parser.parseExpression(LOWEST)
+
+	if !parser.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	if !parser.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	for !parser.isPeekToken(token.RBRACE) {
+		parser.nextToken()
+
+		arm := parser.parseMatchArm()
+		if arm == nil {
+			return nil
+		}
+
+		expression.Arms = append(expression.Arms, arm)
+
+		// Arms are separated by commas, which are optional after a block
+		_, isBlock := arm.Body.(*ast.BlockStatement)
+		if parser.isPeekToken(token.COMMA) || (!isBlock && !parser.isPeekToken(token.RBRACE)) {
+			if !parser.expectPeek(token.COMMA) {
+				return nil
+			}
+		}
+	}
+
+	parser.nextToken()
+	return expression
+}
+
+func (parser *Parser) parseMatchArm() *ast.MatchArm {
+	arm := &ast.MatchArm{
+		Pattern: parser.parsePattern(),
+	}
+
+	if arm.Pattern == nil {
+		return nil
+	}
+
+	if !parser.expectPeek(token.FAT_ARROW) {
+		return nil
+	}
+
+	arm.Token = parser.currentToken
+	parser.nextToken()
+
+	if parser.isCurrentToken(token.LBRACE) {
+		arm.Body = parser.parseBlockStatement()
+		return arm
+	}
+
+	body := parser.parseExpression(LOWEST)
+	if body == nil {
+		return nil
+	}
+
+	arm.Body = body
+	return arm
+}
+
+// Patterns are literals compared to the value, identifiers binding it, or array and hash shapes
+func (parser *Parser) parsePattern() ast.Expression {
+	switch parser.currentToken.Type {
+
+	case token.IDENTIFIER:
+		return &ast.Identifier{
+			Token: parser.currentToken,
+			Value: parser.currentToken.Literal,
+		}
+
+	case token.LBRACKET:
+		return parser.parseArrayPattern()
+
+	case token.LBRACE:
+		return parser.parseHashPattern()
+
+	default:
+		return parser.parseLiteralPattern()
+
+	}
+}
+
+func (parser *Parser) parseLiteralPattern() ast.Expression {
+	switch parser.currentToken.Type {
+
+	case token.INT, token.FLOAT, token.STRING, token.TRUE, token.FALSE, token.NULL:
+		return parser.prefixParsingFunctions[parser.currentToken.Type]()
+
+	case token.MINUS:
+		if !parser.isPeekToken(token.INT) && !parser.isPeekToken(token.FLOAT) {
+			break
+		}
+
+		expression := &ast.PrefixExpression{
+			Token:    parser.currentToken,
+			Operator: parser.currentToken.Literal,
+		}
+
+		parser.nextToken()
+		expression.Expression = parser.prefixParsingFunctions[parser.currentToken.Type]()
+
+		if expression.Expression == nil {
+			return nil
+		}
+
+		return expression
+
+	}
+
+	parser.addInvalidPatternError(parser.currentToken)
+	return nil
+}
+
+func (parser *Parser) parseRestPattern() *ast.Identifier {
+	if !parser.expectPeek(token.IDENTIFIER) {
+		return nil
+	}
+
+	return &ast.Identifier{
+		Token: parser.currentToken,
+		Value: parser.currentToken.Literal,
+	}
+}
+
+// Bindings are identifiers, or array and hash patterns destructuring the value
+func (parser *Parser) parseBindingPattern() ast.Expression {
+	switch parser.peekToken.Type {
+
+	case token.LBRACKET, token.LBRACE:
+		parser.nextToken()
+		return parser.parsePattern()
+
+	default:
+		if !parser.expectPeek(token.IDENTIFIER) {
+			return nil
+		}
+
+		return parser.parsePattern()
+
+	}
+}
+
+func (parser *Parser) parseArrayPattern() ast.Expression {
+	pattern := &ast.ArrayPattern{
+		Token: parser.currentToken,
+	}
+
+	for !parser.isPeekToken(token.RBRACKET) {
+		parser.nextToken()
+
+		// The rest pattern has to be the last element
+		if parser.isCurrentToken(token.ELLIPSIS) {
+			pattern.Rest = parser.parseRestPattern()
+			if pattern.Rest == nil || !parser.expectPeek(token.RBRACKET) {
+				return nil
+			}
+
+			return pattern
+		}
+
+		element := parser.parsePattern()
+		if element == nil {
+			return nil
+		}
+
+		pattern.Elements = append(pattern.Elements, element)
+
+		if !parser.isPeekToken(token.RBRACKET) && !parser.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	parser.nextToken()
+	return pattern
+}
+
+func (parser *Parser) parseHashPattern() ast.Expression {
+	pattern := &ast.HashPattern{
+		Token: parser.currentToken,
+	}
+
+	for !parser.isPeekToken(token.RBRACE) {
+		parser.nextToken()
+
+		// The rest pattern has to be the last pair
+		if parser.isCurrentToken(token.ELLIPSIS) {
+			pattern.Rest = parser.parseRestPattern()
+			if pattern.Rest == nil || !parser.expectPeek(token.RBRACE) {
+				return nil
+			}
+
+			return pattern
+		}
+
+		pair := parser.parseHashPatternPair()
+		if pair == nil {
+			return nil
+		}
+
+		pattern.Pairs = append(pattern.Pairs, pair)
+
+		if !parser.isPeekToken(token.RBRACE) && !parser.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	parser.nextToken()
+	return pattern
+}
+
+func (parser *Parser) parseHashPatternPair() *ast.HashPatternPair {
+	// A lone identifier, as in {name}, binds the value of its own "name" key
+	if parser.isCurrentToken(token.IDENTIFIER) && !parser.isPeekToken(token.COLON) {
+		return &ast.HashPatternPair{
+			Key: &ast.StringLiteral{
+				Token: parser.currentToken,
+				Value: parser.currentToken.Literal,
+			},
+			Value: &ast.Identifier{
+				Token: parser.currentToken,
+				Value: parser.currentToken.Literal,
+			},
+		}
+	}
+
+	if parser.isCurrentToken(token.NULL) {
+		parser.addInvalidPatternError(parser.currentToken)
+		return nil
+	}
+
+	pair := &ast.HashPatternPair{
+		Key: parser.parseLiteralPattern(),
+	}
+
+	if pair.Key == nil || !parser.expectPeek(token.COLON) {
+		return nil
+	}
+
+	parser.nextToken()
+	pair.Value = parser.parsePattern()
+
+	if pair.Value == nil {
+		return nil
+	}
+
+	return pair
+}
+
+func (parser *Parser) parseFunction() ast.Expression {
+	function := &ast.Function{
+		Token: parser.currentToken,
+	}
+
+	if !parser.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	function.Parameters = parser.parseFunctionParameters()
+
+	if !parser.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	// Loops cannot be broken out of from inside a function
+	enclosingLoopLabels := parser.loopLabels
+	parser.loopLabels = nil
+	function.Body = parser.parseBlockStatement()
+	parser.loopLabels = enclosingLoopLabels
+
+	return function
+}
+
+func (parser *Parser) parseFunctionParameters() []*ast.Parameter {
+	parameters := []*ast.Parameter{}
+
+	if parser.isPeekToken(token.RPAREN) {
+		parser.nextToken()
+		return parameters
+	}
+
+	for {
+		parameter := &ast.Parameter{
+			Token: parser.peekToken,
+		}
+
+		// The rest parameter has to be the last one
+		if parser.isPeekToken(token.ELLIPSIS) {
+			parser.nextToken()
+
+			rest := parser.parseRestPattern()
+			if rest == nil {
+				return nil
+			}
+
+			parameter.Pattern = rest
+			parameter.Rest = true
+			parameters = append(parameters, parameter)
+			break
+		}
+
+		parameter.Pattern = parser.parseBindingPattern()
+		if parameter.Pattern == nil {
+			return nil
+		}
+
+		if parser.isPeekToken(token.ASSIGN) {
+			parser.nextToken()
+			parser.nextToken()
+
+			parameter.Default = parser.parseExpression(LOWEST)
+			if parameter.Default == nil {
+				return nil
+			}
+		}
+
+		parameters = append(parameters, parameter)
+
+		if !parser.isPeekToken(token.COMMA) {
+			break
+		}
+
+		parser.nextToken()
+	}
+
+	if !parser.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	return parameters
+}
+
+func (parser *Parser) parseCallExpression(function ast.Expression) ast.Expression {
+	expression := &ast.CallExpression{
+		Token:    parser.currentToken,
+		Function: function,
+	}
+
+	expression.Arguments = parser.parseCallArguments()
+	if expression.Arguments == nil {
+		return nil
+	}
+
+	return expression
+}
+
+func (parser *Parser) parseCallArguments() []ast.Expression {
+	arguments := []ast.Expression{}
+	isNamed := false
+
+	for !parser.isPeekToken(token.RPAREN) {
+		parser.nextToken()
+
+		var argument ast.Expression
+		if parser.isCurrentToken(token.IDENTIFIER) && parser.isPeekToken(token.COLON) {
+			argument = parser.parseNamedArgument()
+			isNamed = true
+		} else if isNamed {
+			parser.addError(
+				diagnostic.INVALID_ARGUMENT,
+				parser.currentToken,
+				"Positional argument %s after named arguments",
+				parser.currentToken.Literal,
+			)
+			return nil
+		} else {
+			argument = parser.parseExpression(LOWEST)
+		}
+
+		if argument == nil {
+			return nil
+		}
+
+		arguments = append(arguments, argument)
+
+		if !parser.isPeekToken(token.RPAREN) && !parser.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	parser.nextToken()
+	return arguments
+}
+
+func (parser *Parser) parseNamedArgument() ast.Expression {
+	argument := &ast.NamedArgument{
+		Token: parser.currentToken,
+		Name: &ast.Identifier{
+			Token: parser.currentToken,
+			Value: parser.currentToken.Literal,
+		},
+	}
+
+	// Skip the colon
+	parser.nextToken()
+	parser.nextToken()
+
+	argument.Value = parser.parseExpression(LOWEST)
+	if argument.Value == nil {
+		return nil
+	}
+
+	return argument
+}
+
+func (parser *Parser) parseSpreadExpression() ast.Expression {
+	expression := &ast.SpreadExpression{
+		Token: parser.currentToken,
+	}
+
+	parser.nextToken()
+	expression.Expression = parser.parseExpression(PREFIX)
+
+	if expression.Expression == nil {
+		return nil
 	}
 
 	return expression
@@ -545,12 +1536,15 @@ func (parser *Parser) parseHashLiteral() ast.Expression {
 			hash.Pairs[key] = value
 		}
 
-		if !parser.isPeekToken(token.RBRACE) && !parser.isPeekToken(token.COMMA) {
-			parser.addUnexepectedTokenError(token.COMMA, parser.currentToken)
+		if !parser.isPeekToken(token.RBRACE) && !parser.expectPeek(token.COMMA) {
 			return nil
 		}
 	}
 
+	if !parser.expectPeek(token.RBRACE) {
+		return nil
+	}
+
 	return hash
 }
 
@@ -560,9 +1554,44 @@ func (parser *Parser) parseAccessExpression(accessor ast.Expression) ast.Express
 		Accessor: accessor,
 	}
 
+	if !parser.expectPeek(token.IDENTIFIER) {
+		return nil
+	}
+
+	expression.Accessed = &ast.Identifier{
+		Token: parser.currentToken,
+		Value: parser.currentToken.Literal,
+	}
+
+	return expression
+}
+
+// Parses both "accessor?.accessed" and "left?.[index]"
+func (parser *Parser) parseOptionalChainExpression(left ast.Expression) ast.Expression {
+	if !parser.isPeekToken(token.LBRACKET) {
+		expression := parser.parseAccessExpression(left)
+		if expression == nil {
+			return nil
+		}
+
+		expression.(*ast.AccessExpression).Optional = true
+		return expression
+	}
+
+	expression := &ast.IndexExpression{
+		Token:    parser.currentToken,
+		Left:     left,
+		Optional: true,
+	}
+
+	// Skip the opening bracket
 	parser.nextToken()
+	parser.nextToken()
+	expression.Index = parser.parseExpression(LOWEST)
 
-	expression.Accessed = parser.parseExpression(LOWEST)
+	if !parser.expectPeek(token.RBRACKET) {
+		return nil
+	}
 
 	return expression
 }
@@ -577,13 +1606,35 @@ func (parser *Parser) isPeekToken(token token.TokenType) bool {
 	return parser.peekToken.Type == token
 }
 
+func isAssignmentTarget(expression ast.Expression) bool {
+	switch expression.(type) {
+
+	case *ast.Identifier, *ast.IndexExpression:
+		return true
+
+	default:
+		return false
+
+	}
+}
+
+func (parser *Parser) isLoopLabel(label string) bool {
+	for _, loopLabel := range parser.loopLabels {
+		if loopLabel == label {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (parser *Parser) expectPeek(token token.TokenType) bool {
 	if parser.isPeekToken(token) {
 		parser.nextToken()
 		return true
 	}
 
-	parser.addUnexepectedTokenError(token, parser.currentToken)
+	parser.addUnexepectedTokenError(token, parser.peekToken)
 	parser.nextToken()
 	return false
 }
@@ -608,27 +1659,53 @@ func (parser *Parser) getPeekPrecedence() int {
 
 // Errors
 
+func (parser *Parser) GetDiagnostics() []*diagnostic.Diagnostic {
+	return parser.diagnostics
+}
+
+// Diagnostics in their single line format
 func (parser *Parser) GetErrors() []string {
-	return parser.errors
+	errors := []string{}
+	for _, parserDiagnostic := range parser.diagnostics {
+		errors = append(errors, parserDiagnostic.String())
+	}
+
+	return errors
+}
+
+func (parser *Parser) addError(code string, spanned token.Token, format string, a ...interface{}) {
+	if parser.panicking {
+		return
+	}
+
+	parser.panicking = true
+	parser.diagnostics = append(parser.diagnostics, diagnostic.New(
+		code,
+		fmt.Sprintf(format, a...),
+		parser.Filepath,
+		diagnostic.TokenSpan(spanned),
+	))
 }
 
 func (parser *Parser) addUnexepectedTokenError(expectedType token.TokenType, unexpected token.Token) {
-	message := fmt.Sprintf(
-		"Expected token %s, got %s instead (l.%d:p.%d)",
+	parser.addError(
+		diagnostic.UNEXPECTED_TOKEN,
+		unexpected,
+		"Expected token %s, got %s instead",
 		expectedType,
 		unexpected.Type,
-		unexpected.Line,
-		unexpected.Position,
 	)
-	parser.errors = append(parser.errors, message)
+}
+
+func (parser *Parser) addInvalidPatternError(invalid token.Token) {
+	parser.addError(diagnostic.INVALID_PATTERN, invalid, "Invalid pattern %q", invalid.Literal)
 }
 
 func (parser *Parser) addIllegalTokenError(token token.Token) {
-	message := fmt.Sprintf(
-		"Illegal token %q found (l.%d:p.%d)",
-		token.Literal,
-		token.Line,
-		token.Position,
-	)
-	parser.errors = append(parser.errors, message)
+	if token.Reason != "" {
+		parser.addError(diagnostic.ILLEGAL_TOKEN, token, "%s", token.Reason)
+		return
+	}
+
+	parser.addError(diagnostic.ILLEGAL_TOKEN, token, "Illegal token %q found", token.Literal)
 }
@@ -7,47 +7,92 @@ type Token struct {
 	Literal  string
 	Line     int
 	Position int
+
+	// Characters spanned in the source when they differ from the literal, as for strings
+	Length int
+
+	// Why an ILLEGAL token was rejected, empty for stray characters
+	Reason string
 }
 
 var keywords = map[string]TokenType{
-	"let":    LET,
-	"fn":     FUNCTION,
-	"return": RETURN,
-	"true":   TRUE,
-	"false":  FALSE,
-	"if":     IF,
-	"else":   ELSE,
-	"import": IMPORT,
-	"export": EXPORT,
+	"let":      LET,
+	"fn":       FUNCTION,
+	"return":   RETURN,
+	"true":     TRUE,
+	"false":    FALSE,
+	"if":       IF,
+	"else":     ELSE,
+	"import":   IMPORT,
+	"export":   EXPORT,
+	"while":    WHILE,
+	"for":      FOR,
+	"in":       IN,
+	"break":    BREAK,
+	"continue": CONTINUE,
+	"null":     NULL,
+	"match":    MATCH,
+	"try":      TRY,
+	"catch":    CATCH,
+	"finally":  FINALLY,
+	"throw":    THROW,
 }
 
 const (
 	// Misc
 	ILLEGAL = "ILLEGAL"
 	EOF     = "EOF"
+	COMMENT = "COMMENT"
 
 	// Identifiers + literals
 	IDENTIFIER = "IDENTIFIER"
 	INT        = "INT"
+	FLOAT      = "FLOAT"
 	STRING     = "STRING"
 
+	// Interpolated strings are split around their expressions, as in "HEAD ${a} MIDDLE ${b} TAIL"
+	TEMPLATE_HEAD   = "TEMPLATE_HEAD"
+	TEMPLATE_MIDDLE = "TEMPLATE_MIDDLE"
+	TEMPLATE_TAIL   = "TEMPLATE_TAIL"
+
 	// Operators
-	ASSIGN       = "="
-	EQUALS       = "=="
-	NOT_EQUALS   = "!="
-	GREATER_THAN = ">"
-	LESS_THAN    = "<"
-	PLUS         = "+"
-	MINUS        = "-"
-	SLASH        = "/"
-	ASTERISK     = "*"
-	NOT          = "!"
+	ASSIGN          = "="
+	PLUS_ASSIGN     = "+="
+	MINUS_ASSIGN    = "-="
+	ASTERISK_ASSIGN = "*="
+	SLASH_ASSIGN    = "/="
+	EQUALS          = "=="
+	NOT_EQUALS      = "!="
+	GREATER_THAN    = ">"
+	LESS_THAN       = "<"
+	GREATER_EQUALS  = ">="
+	LESS_EQUALS     = "<="
+	PLUS            = "+"
+	MINUS           = "-"
+	SLASH           = "/"
+	ASTERISK        = "*"
+	PERCENT         = "%"
+	POWER           = "**"
+	NOT             = "!"
+	AND             = "&&"
+	OR              = "||"
+	AMPERSAND       = "&"
+	PIPE            = "|"
+	CARET           = "^"
+	TILDE           = "~"
+	SHIFT_LEFT      = "<<"
+	SHIFT_RIGHT     = ">>"
+	QUESTION        = "?"
+	QUESTION_DOT    = "?."
+	NULLISH         = "??"
 
 	// Delimiters
 	DOT       = "."
+	ELLIPSIS  = "..."
 	COMMA     = ","
 	SEMICOLON = ";"
 	COLON     = ":"
+	FAT_ARROW = "=>"
 	LPAREN    = "("
 	RPAREN    = ")"
 	LBRACE    = "{"
@@ -65,6 +110,17 @@ const (
 	FALSE    = "FALSE"
 	IMPORT   = "IMPORT"
 	EXPORT   = "EXPORT"
+	WHILE    = "WHILE"
+	FOR      = "FOR"
+	IN       = "IN"
+	BREAK    = "BREAK"
+	CONTINUE = "CONTINUE"
+	NULL     = "NULL"
+	MATCH    = "MATCH"
+	TRY      = "TRY"
+	CATCH    = "CATCH"
+	FINALLY  = "FINALLY"
+	THROW    = "THROW"
 )
 
 func LookupIdentifier(identifier string) TokenType {
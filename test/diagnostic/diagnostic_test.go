@@ -0,0 +1,213 @@
+package diagnostic_test
+
+import (
+	"encoding/json"
+	"errors"
+	"glass/language/diagnostic"
+	"glass/language/lexer"
+	"glass/language/object"
+	"glass/language/token"
+	"strings"
+	"testing"
+)
+
+func TestRender(testing *testing.T) {
+	tests := []struct {
+		diagnostic *diagnostic.Diagnostic
+		expected   string
+	}{
+		{
+			diagnostic.New(diagnostic.UNEXPECTED_TOKEN, "Expected token ), got ; instead", "main.glass", diagnostic.Span{Line: 2, Position: 14, Length: 1}),
+			"error[E0001]: Expected token ), got ; instead\n" +
+				" --> main.glass:2:15\n" +
+				"  |\n" +
+				"2 | let x = (1 + 2;\n" +
+				"  |               ^\n",
+		},
+		{
+			diagnostic.New(diagnostic.INVALID_LITERAL, "could not parse \"0x\" as integer", "main.glass", diagnostic.Span{Line: 3, Position: 9, Length: 2}),
+			"error[E0003]: could not parse \"0x\" as integer\n" +
+				" --> main.glass:3:10\n" +
+				"  |\n" +
+				"3 | \tlet y = 0x;\n" +
+				"  | \t        ^~\n",
+		},
+		{
+			diagnostic.New(diagnostic.ILLEGAL_TOKEN, "Unterminated string", "missing.glass", diagnostic.Span{Line: 1, Position: 0, Length: 3}),
+			"error[E0002]: Unterminated string\n" +
+				" --> missing.glass:1:1\n",
+		},
+		{
+			diagnostic.FromFileError("missing.glass", errors.New("open missing.glass: no such file or directory")),
+			"error[E2000]: Could not read file: open missing.glass: no such file or directory\n" +
+				" --> missing.glass\n",
+		},
+	}
+
+	renderer := diagnostic.NewRenderer(false)
+	renderer.AddSource("main.glass", "let a = 1;\nlet x = (1 + 2;\n\tlet y = 0x;")
+
+	for _, test := range tests {
+		rendered := renderer.Render(test.diagnostic)
+		if rendered != test.expected {
+			testing.Errorf("wrong rendering. expected=\n%s\ngot=\n%s", test.expected, rendered)
+		}
+	}
+}
+
+func TestRenderUnterminatedBlockComment(testing *testing.T) {
+	lines := []string{"let x = 1; /* never", "closed", "at all"}
+	source := strings.Join(lines, "\n")
+
+	nextLine := 1
+	commentLexer := lexer.New(lines[0], func() (string, bool) {
+		if nextLine == len(lines) {
+			return "", true
+		}
+
+		nextLine++
+		return lines[nextLine-1], false
+	})
+
+	var illegal token.Token
+	for illegal = commentLexer.Next(); illegal.Type != token.ILLEGAL; illegal = commentLexer.Next() {
+		if illegal.Type == token.EOF {
+			testing.Fatalf("no ILLEGAL token for the unterminated comment")
+		}
+	}
+
+	renderer := diagnostic.NewRenderer(false)
+	renderer.AddSource("main.glass", source)
+
+	// The underline stops at the end of the line the comment starts on
+	rendered := renderer.Render(diagnostic.New(diagnostic.ILLEGAL_TOKEN, illegal.Reason, "main.glass", diagnostic.TokenSpan(illegal)))
+	expected := "error[E0002]: Unterminated block comment\n" +
+		" --> main.glass:1:12\n" +
+		"  |\n" +
+		"1 | let x = 1; /* never\n" +
+		"  |            ^~~~~~~~\n"
+
+	if rendered != expected {
+		testing.Errorf("wrong rendering. expected=\n%s\ngot=\n%s", expected, rendered)
+	}
+}
+
+func TestRenderColor(testing *testing.T) {
+	renderer := diagnostic.NewRenderer(true)
+	renderer.AddSource("main.glass", "x;")
+
+	rendered := renderer.Render(diagnostic.New("E0006", "message", "main.glass", diagnostic.Span{Line: 1, Position: 0, Length: 1}))
+	expected := "\033[1;31merror\033[0m\033[1;31m[E0006]\033[0m\033[1m: message\033[0m\n" +
+		" \033[1;34m--> \033[0mmain.glass:1:1\n" +
+		" \033[1;34m |\033[0m\n" +
+		"\033[1;34m1 |\033[0m x;\n" +
+		" \033[1;34m |\033[0m \033[1;31m^\033[0m\n"
+
+	if rendered != expected {
+		testing.Errorf("wrong rendering. expected=%q, got=%q", expected, rendered)
+	}
+}
+
+func TestFromError(testing *testing.T) {
+	err := &object.Error{
+		Kind:     object.ARITHMETIC_ERROR,
+		Message:  "division by zero: 1 / 0",
+		File:     "main.glass",
+		Line:     2,
+		Position: 13,
+		Stack: []object.StackFrame{
+			{Function: "divide", File: "main.glass", Line: 5, Position: 6},
+			{Function: "<anonymous>", File: "lib.glass", Line: 1, Position: 0},
+		},
+	}
+
+	converted := diagnostic.FromError(err)
+
+	if converted.Code != diagnostic.ARITHMETIC_ERROR {
+		testing.Errorf("wrong code. expected=%s, got=%s", diagnostic.ARITHMETIC_ERROR, converted.Code)
+	}
+
+	if converted.Message != "ArithmeticError: division by zero: 1 / 0" {
+		testing.Errorf("wrong message. got=%q", converted.Message)
+	}
+
+	if converted.File != "main.glass" || converted.Span != (diagnostic.Span{Line: 2, Position: 13, Length: 1}) {
+		testing.Errorf("wrong location. got=%s %+v", converted.File, converted.Span)
+	}
+
+	expectedNotes := []string{
+		"in divide, called from main.glass:5:7",
+		"in <anonymous>, called from lib.glass:1:1",
+	}
+
+	if len(converted.Notes) != len(expectedNotes) {
+		testing.Fatalf("wrong number of notes. expected=%d, got=%d", len(expectedNotes), len(converted.Notes))
+	}
+
+	for i, note := range expectedNotes {
+		if converted.Notes[i] != note {
+			testing.Errorf("notes[%d] is wrong. expected=%q, got=%q", i, note, converted.Notes[i])
+		}
+	}
+
+	custom := diagnostic.FromError(&object.Error{Kind: "CustomError", Message: "bad"})
+	if custom.Code != diagnostic.RUNTIME_ERROR {
+		testing.Errorf("wrong code for custom kind. expected=%s, got=%s", diagnostic.RUNTIME_ERROR, custom.Code)
+	}
+}
+
+func TestTokenSpan(testing *testing.T) {
+	tests := []struct {
+		token    token.Token
+		expected diagnostic.Span
+	}{
+		{token.Token{Type: token.IDENTIFIER, Literal: "prénom", Line: 1, Position: 4}, diagnostic.Span{Line: 1, Position: 4, Length: 6}},
+		{token.Token{Type: token.EOF, Literal: "", Line: 3, Position: 0}, diagnostic.Span{Line: 3, Position: 0, Length: 1}},
+		{token.Token{Type: token.STRING, Literal: "ab", Line: 2, Position: 6, Length: 4}, diagnostic.Span{Line: 2, Position: 6, Length: 4}},
+	}
+
+	for _, test := range tests {
+		span := diagnostic.TokenSpan(test.token)
+		if span != test.expected {
+			testing.Errorf("wrong span for %q. expected=%+v, got=%+v", test.token.Literal, test.expected, span)
+		}
+	}
+}
+
+func TestMarshalJSON(testing *testing.T) {
+	tests := []struct {
+		diagnostic *diagnostic.Diagnostic
+		expected   string
+	}{
+		{
+			diagnostic.New(diagnostic.UNEXPECTED_TOKEN, "Expected token ), got ; instead", "main.glass", diagnostic.Span{Line: 2, Position: 14, Length: 1}),
+			`{"severity":"error","code":"E0001","message":"Expected token ), got ; instead","file":"main.glass","line":2,"column":15,"length":1,"notes":[]}`,
+		},
+		{
+			diagnostic.FromError(&object.Error{
+				Kind:     object.TYPE_ERROR,
+				Message:  "unknown operator: -BOOLEAN",
+				File:     "lib.glass",
+				Line:     1,
+				Position: 8,
+				Stack:    []object.StackFrame{{Function: "f", File: "main.glass", Line: 3, Position: 1}},
+			}),
+			`{"severity":"error","code":"E1001","message":"TypeError: unknown operator: -BOOLEAN","file":"lib.glass","line":1,"column":9,"length":1,"notes":["in f, called from main.glass:3:2"]}`,
+		},
+		{
+			diagnostic.FromFileError("missing.glass", errors.New("open missing.glass: no such file or directory")),
+			`{"severity":"error","code":"E2000","message":"Could not read file: open missing.glass: no such file or directory","file":"missing.glass","line":0,"column":0,"length":0,"notes":[]}`,
+		},
+	}
+
+	for _, test := range tests {
+		encoded, marshalError := json.Marshal(test.diagnostic)
+		if marshalError != nil {
+			testing.Fatalf("could not marshal diagnostic: %s", marshalError)
+		}
+
+		if string(encoded) != test.expected {
+			testing.Errorf("wrong JSON. expected=%s, got=%s", test.expected, encoded)
+		}
+	}
+}
@@ -0,0 +1,1064 @@
+package evaluator_test
+
+import (
+	"glass/language/evaluator"
+	"glass/language/lexer"
+	"glass/language/object"
+	"glass/language/parser"
+	"strings"
+	"testing"
+)
+
+func TestLoops(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"let x = 0; while (x < 5) { let x = x + 1; }; x;", 5},
+		{"let x = 10; while (x < 5) { let x = x + 1; }; x;", 10},
+		{"let f = fn() { let x = 0; while (true) { let x = x + 1; if (x > 2) { return x; }; }; }; f();", 3},
+		{"let f = fn() { for (let i = 0; i < 10; let i = i + 1) { if (i > 3) { return i; }; }; }; f();", 4},
+		{"let f = fn(array) { for (x in array) { if (x > 15) { return x; }; }; }; f([10, 20, 30]);", 20},
+		{"let total = 0; for (let i = 0;; i += 1) { if (i > 3) { break; }; total += i; }; total;", 6},
+		{"let total = 0; for (let i = 0; i < 4;) { total += i; i += 1; }; total;", 6},
+		{"let total = 0; for (let i = 0;;) { i += 1; total += i; if (i == 3) { break; }; }; total;", 6},
+		{"let i = 0; for (; i < 3;) { i += 1; }; i;", 3},
+		{"let i = 0; for (i = 5;; i += 1) { break; }; i;", 5},
+		{"let fs = []; for (i in [1, 2, 3]) { fs = [...fs, fn() { i }]; }; fs[0]() * 10 + fs[2]();", 13},
+	}
+
+	for _, test := range tests {
+		testIntegerObject(testing, testEvaluate(testing, test.input), test.expected)
+	}
+}
+
+func TestForInHashOrder(testing *testing.T) {
+	input := `let keys = "";
+	for (key in {"d": 1, "b": 2, 10: 3, "a": 4, 2: 5, 1.5: 6, true: 7, false: 8, "c": 9}) {
+		keys = keys + "${key} ";
+	};
+	keys;`
+
+	// Repeated, as an unordered iteration could still match by chance
+	for i := 0; i < 20; i++ {
+		testStringObject(testing, testEvaluate(testing, input), "false true 1.5 2 10 a b c d ")
+	}
+}
+
+func TestLoopControl(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"let x = 0; while (true) { let x = x + 1; if (x == 3) { break; }; }; x;", 3},
+		{"let x = 0; let y = 0; while (x < 5) { let x = x + 1; if (x == 2) { continue; }; let y = y + 1; }; y;", 4},
+		{"let f = fn() { for (let i = 0; i < 10; let i = i + 1) { if (i == 2) { break; }; }; 7; }; f();", 7},
+		{"let f = fn() { for (let i = 0; i < 5; let i = i + 1) { continue; return 1; }; 2; }; f();", 2},
+		{"let f = fn() { outer: for (a in [1, 2, 3]) { for (b in [1, 2, 3]) { if (b == 2) { continue outer; }; if (a == 3) { return a * 10 + b; }; }; }; }; f();", 31},
+		{"let x = 0; let y = 0; outer: while (x < 3) { let x = x + 1; while (true) { let y = y + 1; continue outer; }; }; y;", 3},
+		{"let x = 0; outer: while (true) { while (true) { let x = x + 1; if (x == 4) { break outer; }; }; }; x;", 4},
+		{"let x = 0; while (true) { x += 1; let y = if (x == 3) { break; }; }; x;", 3},
+		{"let x = 0; let n = 0; while (x < 4) { x += 1; let y = if (x % 2 == 0) { continue; }; n += 1; }; n;", 2},
+	}
+
+	for _, test := range tests {
+		testIntegerObject(testing, testEvaluate(testing, test.input), test.expected)
+	}
+}
+
+func TestAssignments(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"let x = 1; x = 5; x;", 5},
+		{"let x = 1; let y = x = 7; x + y;", 14},
+		{"let x = 10; x += 5; x -= 3; x *= 2; x /= 4; x;", 6},
+		{"let counter = 0; let increment = fn() { counter += 1; }; increment(); increment(); counter;", 2},
+		{"let total = 0; for (x in [1, 2, 3]) { total += x; }; total;", 6},
+		{"let total = 0; for (let i = 0; i < 4; i += 1) { total += i; }; total;", 6},
+		{"let x = 1; let f = fn() { let x = 2; x = 3; }; f(); x;", 1},
+	}
+
+	for _, test := range tests {
+		testIntegerObject(testing, testEvaluate(testing, test.input), test.expected)
+	}
+}
+
+func TestAssignmentErrors(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"x = 5;", "assignment to undeclared identifier: x"},
+		{"x += 5;", "identifier not found: x"},
+		{"let x = 1; x += true;", "type mismatch: INTEGER + BOOLEAN"},
+	}
+
+	for _, test := range tests {
+		testErrorObject(testing, testEvaluate(testing, test.input), test.expected)
+	}
+}
+
+func TestIndexAssignments(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"let a = [1, 2, 3]; a[1] = 10; a[1];", 10},
+		{"let a = [1, 2, 3]; a[0] += 4; a[0];", 5},
+		{"let a = []; a[0] = 1; a[1] = 2; a[0] + a[1];", 3},
+		{"let a = [1, 2]; let b = a; b[0] = 7; a[0];", 7},
+		{"let grid = [[1, 2], [3, 4]]; grid[1][0] = 9; grid[1][0];", 9},
+		{"let h = {}; h[\"k\"] = 3; h[\"k\"];", 3},
+		{"let h = {\"k\": 1, \"j\": 2}; h[\"k\"] *= 5; h[\"k\"] + h[\"j\"];", 7},
+		{"let h = {}; let set = fn(key) { h[key] = 4; }; set(true); h[true];", 4},
+	}
+
+	for _, test := range tests {
+		testIntegerObject(testing, testEvaluate(testing, test.input), test.expected)
+	}
+}
+
+func TestIndexAssignmentErrors(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let a = [1]; a[3] = 1;", "index out of range: 3 with length 1"},
+		{"let a = [1]; a[-1] = 1;", "index out of range: -1 with length 1"},
+		{"let h = {}; h[[1]] = 1;", "unusable as hash key: ARRAY"},
+		{"let s = \"abc\"; s[0] = 1;", "index assignment not supported: STRING"},
+	}
+
+	for _, test := range tests {
+		testErrorObject(testing, testEvaluate(testing, test.input), test.expected)
+	}
+}
+
+func TestFloats(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+	}{
+		{"1.5;", 1.5},
+		{"-2.25;", -2.25},
+		{"1.5 + 2.25;", 3.75},
+		{"1 + 0.5;", 1.5},
+		{"3 * 0.5;", 1.5},
+		{"1.0 / 4;", 0.25},
+		{"10 - 2.5;", 7.5},
+		{"float(3);", 3},
+		{"float(\"2.5\");", 2.5},
+	}
+
+	for _, test := range tests {
+		testFloatObject(testing, testEvaluate(testing, test.input), test.expected)
+	}
+}
+
+func TestFloatComparisons(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"1.5 < 2;", true},
+		{"2 > 1.5;", true},
+		{"1 == 1.0;", true},
+		{"0.1 + 0.2 == 0.3;", false},
+	}
+
+	for _, test := range tests {
+		testBooleanObject(testing, testEvaluate(testing, test.input), test.expected)
+	}
+}
+
+func TestNumericHashKeys(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"{1: \"a\"}[1.0];", "a"},
+		{"{1.0: \"a\"}[1];", "a"},
+		{"{-3: \"a\"}[-3.0];", "a"},
+		{"{0: \"a\"}[-0.0];", "a"},
+		{"{2.5: \"a\"}[2.5];", "a"},
+		{"let h = {}; h[1] = \"a\"; h[1.0] = \"b\"; h[1];", "b"},
+	}
+
+	for _, test := range tests {
+		testStringObject(testing, testEvaluate(testing, test.input), test.expected)
+	}
+
+	testNullObject(testing, testEvaluate(testing, "{1: \"a\"}[1.5];"))
+	testNullObject(testing, testEvaluate(testing, "{2.5: \"a\"}[2];"))
+}
+
+func TestIntegerConversions(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"int(2.9);", 2},
+		{"int(-2.9);", -2},
+		{"int(\"42\");", 42},
+		{"int(7);", 7},
+	}
+
+	for _, test := range tests {
+		testIntegerObject(testing, testEvaluate(testing, test.input), test.expected)
+	}
+}
+
+func TestConversionErrors(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"int(\"abc\");", "could not convert \"abc\" to INTEGER"},
+		{"float(true);", "argument to `float` not supported, got BOOLEAN"},
+		{"int(1, 2);", "wrong number of arguments. got=2, want=1"},
+		{"1.5 + true;", "type mismatch: FLOAT + BOOLEAN"},
+		{"\"a\" % \"b\";", "unknown operator: STRING % STRING"},
+	}
+
+	for _, test := range tests {
+		testErrorObject(testing, testEvaluate(testing, test.input), test.expected)
+	}
+}
+
+func TestLogicalOperators(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"true && true;", true},
+		{"true && false;", false},
+		{"false || true;", true},
+		{"false || false;", false},
+		{"1 < 2 && 2 < 3;", true},
+		{"1 > 2 || 2 > 3;", false},
+		{"false && true || true;", true},
+		{"true || false && false;", true},
+		{"false && missing;", false},
+		{"true || missing;", true},
+		{"let calls = 0; let f = fn() { calls += 1; true; }; false && f(); calls == 0;", true},
+		{"let calls = 0; let f = fn() { calls += 1; true; }; true && f(); calls == 1;", true},
+	}
+
+	for _, test := range tests {
+		testBooleanObject(testing, testEvaluate(testing, test.input), test.expected)
+	}
+}
+
+func TestNullishOperator(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"null ?? 5;", 5},
+		{"0 ?? 5;", 0},
+		{"null ?? null ?? 3;", 3},
+		{"let h = {}; h[\"missing\"] ?? 7;", 7},
+		{"[1, 2][5] ?? -1;", -1},
+		{"let calls = 0; let f = fn() { calls += 1; 1; }; 2 ?? f(); calls;", 0},
+	}
+
+	for _, test := range tests {
+		testIntegerObject(testing, testEvaluate(testing, test.input), test.expected)
+	}
+
+	testBooleanObject(testing, testEvaluate(testing, "false ?? true;"), false)
+	testBooleanObject(testing, testEvaluate(testing, "null == null;"), true)
+	testNullObject(testing, testEvaluate(testing, "null;"))
+}
+
+func TestOptionalChaining(testing *testing.T) {
+	config := `let config = {"server": {"port": 8080, "hosts": ["a", "b"]}, "empty": null};`
+
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{config + "config.server.port;", 8080},
+		{config + "config?.server?.port;", 8080},
+		{config + "config?.database?.port ?? 5432;", 5432},
+		{config + "config.empty?.port ?? 1;", 1},
+		{"let values = null; values?.[0] ?? 3;", 3},
+		{"let values = [4]; values?.[0];", 4},
+	}
+
+	for _, test := range tests {
+		testIntegerObject(testing, testEvaluate(testing, test.input), test.expected)
+	}
+
+	testStringObject(testing, testEvaluate(testing, config+"config?.server?.hosts?.[1];"), "b")
+	testStringObject(testing, testEvaluate(testing, config+"config.server.hosts[0];"), "a")
+	testNullObject(testing, testEvaluate(testing, config+"config.database;"))
+	testNullObject(testing, testEvaluate(testing, "let missing = null; missing?.value;"))
+
+	// The rest of the chain is skipped once an optional link sees null
+	testNullObject(testing, testEvaluate(testing, "let a = null; a?.b.c;"))
+	testNullObject(testing, testEvaluate(testing, "let a = null; a?.[0].x;"))
+	testNullObject(testing, testEvaluate(testing, "let a = null; a?.b.c[0];"))
+	testNullObject(testing, testEvaluate(testing, "let a = null; a?.f(missing);"))
+	testIntegerObject(testing, testEvaluate(testing, "let a = null; a?.b.c ?? 7;"), 7)
+	testErrorObject(testing, testEvaluate(testing, "let missing = null; missing.value;"), "unsuported access type NULL")
+}
+
+func TestElseIfExpressions(testing *testing.T) {
+	sign := `let sign = fn(x) {
+		if (x < 0) {
+			return -1;
+		} else if (x == 0) {
+			return 0;
+		} else {
+			return 1;
+		}
+	};`
+
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{sign + "sign(-5);", -1},
+		{sign + "sign(0);", 0},
+		{sign + "sign(5);", 1},
+		{"let x = 2; if (x == 1) { 10 } else if (x == 2) { 20 } else if (x == 3) { 30 };", 20},
+		{"let x = 3; if (x == 1) { 10 } else if (x == 2) { 20 } else if (x == 3) { 30 };", 30},
+		{"let calls = 0; let f = fn() { calls += 1; false }; if (true) { 1 } else if (f()) { 2 }; calls;", 0},
+	}
+
+	for _, test := range tests {
+		testIntegerObject(testing, testEvaluate(testing, test.input), test.expected)
+	}
+
+	testNullObject(testing, testEvaluate(testing, "if (false) { 1 } else if (false) { 2 };"))
+}
+
+func TestMatchExpressions(testing *testing.T) {
+	describe := `let describe = fn(value) {
+		match (value) {
+			0 => "zero",
+			-1 => "minus one",
+			1.5 => "one and a half",
+			"a" => "letter",
+			true => "yes",
+			null => "nothing",
+			[] => "empty",
+			[x, y] => "pair ${x} ${y}",
+			[_, _, [z]] => {
+				let doubled = z * 2;
+				"nested ${doubled}"
+			}
+			{"type": "circle", radius} => "circle ${radius}",
+			{"type": shape} => "shape ${shape}",
+			other => "other ${other}",
+		}
+	};`
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{describe + "describe(0);", "zero"},
+		{describe + "describe(-1);", "minus one"},
+		{describe + "describe(1.5);", "one and a half"},
+		{describe + "describe(\"a\");", "letter"},
+		{describe + "describe(true);", "yes"},
+		{describe + "describe(false);", "other false"},
+		{describe + "describe(null);", "nothing"},
+		{describe + "describe([]);", "empty"},
+		{describe + "describe([1, 2]);", "pair 1 2"},
+		{describe + "describe([1, 2, [4]]);", "nested 8"},
+		{describe + "describe([1, 2, 3]);", "other [1, 2, 3]"},
+		{describe + "describe({\"type\": \"circle\", \"radius\": 3});", "circle 3"},
+		{describe + "describe({\"type\": \"square\", \"side\": 2});", "shape square"},
+		{describe + "describe(1);", "other 1"},
+		{describe + "describe(\"0\");", "other 0"},
+	}
+
+	for _, test := range tests {
+		testStringObject(testing, testEvaluate(testing, test.input), test.expected)
+	}
+}
+
+func TestMatchExpressionScopes(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"let x = 1; match ([5]) { [x] => x }; x;", 1},
+		{"let f = fn(v) { match (v) { 1 => { return 10; } _ => 0 }; 20 }; f(1);", 10},
+		{"let f = fn(v) { match (v) { 1 => { return 10; } _ => 0 }; 20 }; f(2);", 20},
+		{"let total = 0; for (v in [1, [2, 3], 4]) { match (v) { [a, b] => { total += a + b; } n => { total += n; } } }; total;", 10},
+
+		// Signals from arms and blocks used as values leave the expression they are in
+		{"let i = 0; let total = 0; while (i < 4) { i += 1; let x = match (i) { 2 => { continue; } _ => i }; total += x; }; total;", 8},
+		{"let f = fn(v) { let r = match (v) { _ => { return -1; } }; 5 }; f(1);", -1},
+		{"let n = 0; for (x in [1, 2]) { print(if (true) { continue; }); n += 1; }; n;", 0},
+		{"let f = fn() { 1 + if (true) { return 5; } }; f();", 5},
+		{"let f = fn() { [1][if (true) { return 6; }] }; f();", 6},
+		{"let f = fn() { throw if (true) { return 7; } }; f();", 7},
+		{"let f = fn() { let h = {\"a\": match (1) { _ => { return 8; } }}; 0 }; f();", 8},
+	}
+
+	for _, test := range tests {
+		testIntegerObject(testing, testEvaluate(testing, test.input), test.expected)
+	}
+
+	testErrorObject(testing, testEvaluate(testing, "match (3) { 1 => 1, 2 => 2 };"), "no match arm for value 3")
+	testErrorObject(testing, testEvaluate(testing, "match ([1]) { [a, b] => a };"), "no match arm for value [1]")
+
+	// Numbers match across integers and floats, like 1 == 1.0
+	testIntegerObject(testing, testEvaluate(testing, "match (1.0) { 1 => 1, _ => 2 };"), 1)
+	testIntegerObject(testing, testEvaluate(testing, "match (2) { 2.0 => 1, _ => 2 };"), 1)
+	testIntegerObject(testing, testEvaluate(testing, "match (1.5) { 1 => 1, _ => 2 };"), 2)
+	testIntegerObject(testing, testEvaluate(testing, "let [1, a] = [1.0, 3]; a;"), 3)
+
+	// Calls to empty functions are matched as null
+	testIntegerObject(testing, testEvaluate(testing, "let f = fn() {}; match (f()) { null => 1, _ => 2 };"), 1)
+	testErrorObject(testing, testEvaluate(testing, "let f = fn() {}; match (f()) { 1 => 1 };"), "no match arm for value null")
+}
+
+func TestDestructuring(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"let [a, b] = [1, 2]; a * 10 + b;", 12},
+		{"let [a, ...rest] = [1, 2, 3]; a + rest[0] + rest[1];", 6},
+		{"let [...all] = [4, 5]; all[1];", 5},
+		{"let [a, [b, c]] = [1, [2, 3]]; a + b + c;", 6},
+		{"let [_, second] = [1, 2]; second;", 2},
+		{"let {age} = {\"name\": \"Zoé\", \"age\": 30}; age;", 30},
+		{"let {\"size\": [w, h]} = {\"size\": [3, 4]}; w * h;", 12},
+		{"let {id, ...others} = {\"id\": 1, \"a\": 2, \"b\": 3}; others[\"a\"] + others[\"b\"];", 5},
+		{"let {id, ...others} = {\"id\": 1}; others[\"id\"] ?? 7;", 7},
+		{"let add = fn([a, b]) { a + b }; add([3, 4]);", 7},
+		{"let area = fn({width, height}) { width * height }; area({\"width\": 2, \"height\": 5});", 10},
+		{"let f = fn(x, [y, ...ys]) { x + y + ys[0] }; f(1, [2, 3, 4]);", 6},
+	}
+
+	for _, test := range tests {
+		testIntegerObject(testing, testEvaluate(testing, test.input), test.expected)
+	}
+}
+
+func TestDestructuringErrors(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let [a, b] = [1];", "cannot destructure array of length 1 into [a, b]"},
+		{"let [a] = [1, 2];", "cannot destructure array of length 2 into [a]"},
+		{"let [a, b, ...rest] = [1];", "cannot destructure array of length 1 into [a, b, ...rest]"},
+		{"let [a, b] = 5;", "cannot destructure INTEGER into [a, b]"},
+		{"let {name} = [1];", "cannot destructure ARRAY into {name:name}"},
+		{"let {name, age} = {\"name\": \"Zoé\"};", "cannot destructure hash without key age into {name:name, age:age}"},
+		{"let [a, [b, c]] = [1, [2]];", "cannot destructure array of length 1 into [b, c]"},
+		{"let [1, a] = [2, 3];", "cannot destructure 2 into 1"},
+		{"let add = fn([a, b]) { a + b }; add(5);", "cannot destructure INTEGER into [a, b]"},
+		{"let f = fn() {}; let [a] = f();", "cannot destructure NULL into [a]"},
+		{"let f = fn() {}; let {a} = f();", "cannot destructure NULL into {a:a}"},
+	}
+
+	for _, test := range tests {
+		testErrorObject(testing, testEvaluate(testing, test.input), test.expected)
+	}
+}
+
+func TestFunctionParameters(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"let f = fn(a, b = 2) { a * 10 + b }; f(1);", 12},
+		{"let f = fn(a, b = 2) { a * 10 + b }; f(1, 3);", 13},
+		{"let f = fn(a, b = a + 1) { a * 10 + b }; f(4);", 45},
+		{"let f = fn(a, b = 2, c = 3) { a * 100 + b * 10 + c }; f(1, c: 5);", 125},
+		{"let f = fn(a, b) { a - b }; f(b: 1, a: 5);", 4},
+		{"let f = fn(...rest) { rest[0] ?? 0 }; f();", 0},
+		{"let f = fn(first, ...rest) { first + rest[0] + rest[1] }; f(1, 2, 3);", 6},
+		{"let f = fn(a, b, c) { a * 100 + b * 10 + c }; f(...[1, 2, 3]);", 123},
+		{"let f = fn(a, b, c) { a * 100 + b * 10 + c }; f(1, ...[2], ...[3]);", 123},
+		{"let f = fn(...rest) { rest[2] }; let values = [4, 5]; f(...values, 6);", 6},
+		{"let values = [...[1, 2], 3, ...[]]; values[2];", 3},
+		{"let f = fn(a, [b, c] = [2, 3]) { a + b + c }; f(1);", 6},
+	}
+
+	for _, test := range tests {
+		testIntegerObject(testing, testEvaluate(testing, test.input), test.expected)
+	}
+}
+
+func TestFunctionParameterErrors(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let f = fn(a, b) { a }; f(1);", "missing argument for parameter b"},
+		{"let f = fn(a) { a }; f(1, 2);", "wrong number of arguments. got=2, want=1"},
+		{"let f = fn() { 1 }; f(1);", "wrong number of arguments. got=1, want=0"},
+		{"let f = fn(a, b = 2) { a }; f(1, 2, 3);", "wrong number of arguments. got=3, want=2"},
+		{"let f = fn(a) { a }; f(1, a: 2);", "multiple values for argument a"},
+		{"let f = fn(a) { a }; f(a: 1, a: 2);", "multiple values for argument a"},
+		{"let f = fn(a) { a }; f(a: 1, c: 2, b: 3);", "unknown named arguments: b, c"},
+		{"let f = fn(...rest) { rest }; f(rest: 1);", "unknown named arguments: rest"},
+		{"let f = fn(a, b) { a }; f(...5);", "cannot spread INTEGER, expected ARRAY"},
+		{"let f = fn(a, b = missing) { a }; f(1);", "identifier not found: missing"},
+		{"let x = ...[1];", "cannot spread [1] outside of a call or an array"},
+		{"print(value: 1);", "builtin functions do not accept named arguments"},
+	}
+
+	for _, test := range tests {
+		testErrorObject(testing, testEvaluate(testing, test.input), test.expected)
+	}
+}
+
+func TestTryStatements(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let r = \"\"; try { throw \"boom\"; } catch (e) { r = e.message; }; r;", "boom"},
+		{"let r = \"\"; try { throw \"boom\"; } catch (e) { r = e.kind; }; r;", "Error"},
+		{"let r = \"\"; try { missing; } catch (e) { r = e.kind + \": \" + e.message; }; r;", "ReferenceError: identifier not found: missing"},
+		{"let r = \"\"; try { 1 + true; } catch (e) { r = e.kind; }; r;", "TypeError"},
+		{"let r = \"\"; try { 1 / 0; } catch (e) { r = e.kind; }; r;", "ArithmeticError"},
+		{"let r = \"\"; try { [1][\"a\"] = 2; } catch (e) { r = e.kind; }; r;", "TypeError"},
+		{"let r = \"\"; try { let [a] = 1; } catch (e) { r = e.kind; }; r;", "ValueError"},
+		{"let r = \"\"; try { fn(a) { a }(); } catch (e) { r = e.kind; }; r;", "ArgumentError"},
+		{"let r = \"\"; try { throw error(\"bad\", \"CustomError\"); } catch (e) { r = e.kind + \" \" + e.message; }; r;", "CustomError bad"},
+		{"let r = \"\"; try { throw \"a\"; } catch { r = \"caught\"; }; r;", "caught"},
+		{"let r = \"\"; try { r = \"ok\"; } catch (e) { r = \"caught\"; }; r;", "ok"},
+		{"let r = \"\"; try { throw \"a\"; } catch (e) { r += \"c\"; } finally { r += \"f\"; }; r;", "cf"},
+		{"let r = \"\"; try { r += \"t\"; } finally { r += \"f\"; }; r;", "tf"},
+		{"let r = \"\"; try { try { throw \"inner\"; } finally { r += \"f\"; }; } catch (e) { r += e.message; }; r;", "finner"},
+		{"let r = \"\"; try { try { throw \"a\"; } catch (e) { throw e; }; } catch (e) { r = e.message; }; r;", "a"},
+		{"let r = \"\"; try { throw \"a\"; } catch (e) { try { throw \"b\"; } catch (e) { r += e.message; }; r += e.message; }; r;", "ba"},
+	}
+
+	for _, test := range tests {
+		testStringObject(testing, testEvaluate(testing, test.input), test.expected)
+	}
+}
+
+func TestTryStatementControlFlow(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"let f = fn() { try { return 1; } finally { 2; } }; f();", 1},
+		{"let f = fn() { try { return 1; } finally { return 2; } }; f();", 2},
+		{"let f = fn() { try { throw \"a\"; } catch (e) { return 3; } }; f();", 3},
+		{"let f = fn() { try { throw \"a\"; } finally { return 4; } }; f();", 4},
+		{"let calls = 0; let f = fn() { calls += 1; 1 }; let g = fn() { return f(); }; g(); calls;", 1},
+		{"let i = 0; while (true) { try { i += 1; if (i >= 3) { break; }; } finally { i += 10; } }; i;", 22},
+		{"let total = 0; for (v in [1, 2, 3]) { try { if (v == 2) { throw \"skip\"; }; total += v; } catch { continue; } }; total;", 4},
+		{"let r = 0; try { throw 42; } catch (e) { r = e.value; }; r;", 42},
+		{"let r = 0; try { throw {\"code\": 7}; } catch (e) { r = e.value[\"code\"]; }; r;", 7},
+		{"let r = 0; try { missing; } catch (e) { r = e.line * 100 + e.position; }; r;", 117},
+		{"let r = 0; let fail = fn() { throw \"a\"; }; try { fail(); } catch (e) { r = e.line * 100 + e.position; }; r;", 129},
+		{"let r = 0; try { 1 + [2] * 3; } catch (e) { r = e.position; }; r;", 25},
+	}
+
+	for _, test := range tests {
+		testIntegerObject(testing, testEvaluate(testing, test.input), test.expected)
+	}
+
+	testNullObject(testing, testEvaluate(testing, "let r = 0; try { missing; } catch (e) { r = e.value; }; r;"))
+}
+
+func TestThrowErrors(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"throw \"uncaught\";", "uncaught"},
+		{"throw error(\"bad\");", "bad"},
+		{"try { throw \"a\"; } catch (e) { throw \"b\"; };", "b"},
+		{"try { 1; } finally { throw \"c\"; };", "c"},
+		{"try { throw \"a\"; } catch (e) { e.unknown; };", "unknown exception field unknown"},
+		{"error(1);", "arguments to `error` must be STRING, got INTEGER"},
+		{"let f = fn() {}; throw f();", "null"},
+		{"let f = fn() { let x = 1; }; throw f();", "null"},
+	}
+
+	for _, test := range tests {
+		testErrorObject(testing, testEvaluate(testing, test.input), test.expected)
+	}
+}
+
+func TestErrorTracebacks(testing *testing.T) {
+	input := "let divide = fn(a, b) { a / b }; let average = fn(values) { divide(values[0], 0) }; average([1]);"
+
+	result := testEvaluateFile(testing, input, "main.glass", object.NewProgramEnvironment(""))
+	err, ok := result.(*object.Error)
+	if !ok {
+		testing.Fatalf("object is not Error. got=%T (%+v)", result, result)
+	}
+
+	expectedStack := []object.StackFrame{
+		{Function: "divide", File: "main.glass", Line: 1, Position: 60},
+		{Function: "average", File: "main.glass", Line: 1, Position: 84},
+	}
+
+	if len(err.Stack) != len(expectedStack) {
+		testing.Fatalf("stack has wrong length. expected=%d, got=%d (%+v)", len(expectedStack), len(err.Stack), err.Stack)
+	}
+
+	for i, frame := range expectedStack {
+		if err.Stack[i] != frame {
+			testing.Errorf("stack[%d] is wrong. expected=%+v, got=%+v", i, frame, err.Stack[i])
+		}
+	}
+
+	if err.File != "main.glass" || err.Line != 1 || err.Position != 26 {
+		testing.Errorf("error location is wrong. expected=main.glass:1:26, got=%s:%d:%d", err.File, err.Line, err.Position)
+	}
+}
+
+func TestErrorTracebackCallees(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected object.StackFrame
+	}{
+		{"let f = fn() { missing }; f();", object.StackFrame{Function: "f", File: "main.glass", Line: 1, Position: 26}},
+		{"let h = {\"f\": fn() { missing }}; h.f();", object.StackFrame{Function: "<anonymous>", File: "main.glass", Line: 1, Position: 33}},
+		{"let h = [fn() { missing }]; h[0]();", object.StackFrame{Function: "<anonymous>", File: "main.glass", Line: 1, Position: 28}},
+	}
+
+	for _, test := range tests {
+		result := testEvaluateFile(testing, test.input, "main.glass", object.NewProgramEnvironment(""))
+		err, ok := result.(*object.Error)
+		if !ok {
+			testing.Errorf("object is not Error. got=%T (%+v)", result, result)
+			continue
+		}
+
+		if len(err.Stack) != 1 || err.Stack[0] != test.expected {
+			testing.Errorf("wrong stack for %q. expected=%+v, got=%+v", test.input, test.expected, err.Stack)
+		}
+	}
+}
+
+func TestErrorTracebackFunctionNames(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"missing;", []string{}},
+		{"let f = fn() { missing }; f();", []string{"f"}},
+		{"let f = fn() { missing }; let g = f; g();", []string{"f"}},
+		{"fn() { missing }();", []string{"<anonymous>"}},
+		{"let h = {\"f\": fn() { missing }}; h.f();", []string{"<anonymous>"}},
+		{"let f = fn() { try { missing; } catch (e) { throw e; } }; let g = fn() { f() }; g();", []string{"f", "g"}},
+	}
+
+	for _, test := range tests {
+		result := testEvaluate(testing, test.input)
+		err, ok := result.(*object.Error)
+		if !ok {
+			testing.Errorf("object is not Error. got=%T (%+v)", result, result)
+			continue
+		}
+
+		names := []string{}
+		for _, frame := range err.Stack {
+			names = append(names, frame.Function)
+		}
+
+		if strings.Join(names, ", ") != strings.Join(test.expected, ", ") {
+			testing.Errorf("wrong stack for %q. expected=%v, got=%v", test.input, test.expected, names)
+		}
+	}
+
+	testStringObject(testing, testEvaluateFile(testing, "let r = \"\"; try { missing; } catch (e) { r = e.file; }; r;", "main.glass", object.NewProgramEnvironment("")), "main.glass")
+}
+
+func TestConditionalExpressions(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"true ? 1 : 2;", 1},
+		{"false ? 1 : 2;", 2},
+		{"null ? 1 : 2;", 2},
+		{"0 ? 1 : 2;", 1},
+		{"let x = 5; x > 3 ? x * 2 : x;", 10},
+		{"let x = 0; x < 0 ? -1 : x == 0 ? 0 : 1;", 0},
+		{"let x = 7; x < 0 ? -1 : x == 0 ? 0 : 1;", 1},
+		{"let x = 1; let y = x == 1 ? 10 : 20; y;", 10},
+		{"let x = 0; true ? x = 3 : 4; x;", 3},
+		{"null ?? false ? 1 : 2;", 2},
+		{"let calls = 0; let f = fn() { calls += 1; }; true ? 1 : f(); calls;", 0},
+		{"let h = {\"a\": true ? 1 : 2}; h[\"a\"];", 1},
+	}
+
+	for _, test := range tests {
+		testIntegerObject(testing, testEvaluate(testing, test.input), test.expected)
+	}
+}
+
+func TestArithmeticOperators(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"7 % 3;", 1},
+		{"-7 % 3;", -1},
+		{"2 ** 10;", 1024},
+		{"2 ** 3 ** 2;", 512},
+		{"-2 ** 2;", -4},
+		{"(-2) ** 3;", -8},
+		{"2 * 3 ** 2;", 18},
+		{"10 - 6 % 4;", 8},
+		{"5 ** 0;", 1},
+	}
+
+	for _, test := range tests {
+		testIntegerObject(testing, testEvaluate(testing, test.input), test.expected)
+	}
+}
+
+func TestFloatArithmeticOperators(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+	}{
+		{"7.5 % 2;", 1.5},
+		{"2.0 ** 3;", 8},
+		{"4 ** 0.5;", 2},
+		{"2 ** -1;", 0.5},
+	}
+
+	for _, test := range tests {
+		testFloatObject(testing, testEvaluate(testing, test.input), test.expected)
+	}
+}
+
+func TestComparisonOperators(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"1 <= 1;", true},
+		{"1 <= 0;", false},
+		{"2 >= 3;", false},
+		{"3 >= 3;", true},
+		{"1 != 2;", true},
+		{"1 != 1;", false},
+		{"1.5 <= 1.5;", true},
+		{"2 >= 2.5;", false},
+		{"1.5 != 1.5;", false},
+		{"\"abc\" == \"abc\";", true},
+		{"\"abc\" != \"abd\";", true},
+		{"\"abc\" < \"abd\";", true},
+		{"\"b\" >= \"a\";", true},
+		{"\"a\" <= \"A\";", false},
+		{"true != false;", true},
+	}
+
+	for _, test := range tests {
+		testBooleanObject(testing, testEvaluate(testing, test.input), test.expected)
+	}
+}
+
+func TestArithmeticErrors(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1 / 0;", "division by zero: 1 / 0"},
+		{"let x = 0; 5 % x;", "modulo by zero: 5 % 0"},
+		{"let x = 4; x /= 0;", "division by zero: 4 / 0"},
+	}
+
+	for _, test := range tests {
+		testErrorObject(testing, testEvaluate(testing, test.input), test.expected)
+	}
+}
+
+func TestBitwiseOperators(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"12 & 10;", 8},
+		{"12 | 10;", 14},
+		{"12 ^ 10;", 6},
+		{"~0;", -1},
+		{"~5 & 0xF;", 10},
+		{"1 << 4;", 16},
+		{"-16 >> 2;", -4},
+		{"1 << 2 + 1;", 8},
+		{"5 & 3 ^ 6 | 8;", 15},
+		{"1 | 2 & 3;", 3},
+		{"let flags = 0b0101; flags & ~0b0100;", 1},
+	}
+
+	for _, test := range tests {
+		testIntegerObject(testing, testEvaluate(testing, test.input), test.expected)
+	}
+
+	testBooleanObject(testing, testEvaluate(testing, "1 << 2 < 5;"), true)
+	testBooleanObject(testing, testEvaluate(testing, "(6 & 3) == 2;"), true)
+}
+
+func TestBitwiseOperatorErrors(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1.5 & 1;", "bitwise operator & requires INTEGER operands, got FLOAT and INTEGER"},
+		{"1 | true;", "bitwise operator | requires INTEGER operands, got INTEGER and BOOLEAN"},
+		{"\"a\" ^ \"b\";", "bitwise operator ^ requires INTEGER operands, got STRING and STRING"},
+		{"6 & 3 == 2;", "bitwise operator & requires INTEGER operands, got INTEGER and BOOLEAN"},
+		{"~1.5;", "bitwise operator ~ requires an INTEGER operand, got FLOAT"},
+		{"1 << -1;", "negative shift count: 1 << -1"},
+		{"8 >> -2;", "negative shift count: 8 >> -2"},
+	}
+
+	for _, test := range tests {
+		testErrorObject(testing, testEvaluate(testing, test.input), test.expected)
+	}
+}
+
+func TestCheckedArithmetic(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"9223372036854775807 + 1;", "integer overflow: 9223372036854775807 + 1"},
+		{"-9223372036854775807 - 2;", "integer overflow: -9223372036854775807 - 2"},
+		{"4611686018427387904 * 2;", "integer overflow: 4611686018427387904 * 2"},
+		{"let x = -1; x * (-9223372036854775807 - 1);", "integer overflow: -1 * -9223372036854775808"},
+		{"2 ** 63;", "integer overflow: 2 ** 63"},
+	}
+
+	for _, test := range tests {
+		programEnvironment := object.NewProgramEnvironment("")
+		programEnvironment.CheckedArithmetic = true
+
+		testErrorObject(testing, testEvaluateProgram(testing, test.input, programEnvironment), test.expected)
+	}
+
+	// Results close to the limits are still allowed
+	programEnvironment := object.NewProgramEnvironment("")
+	programEnvironment.CheckedArithmetic = true
+
+	evaluated := testEvaluateProgram(testing, "(-2) ** 63 + (2 ** 62 - 1) * 2 + 1;", programEnvironment)
+	testIntegerObject(testing, evaluated, -1)
+
+	// Without checked arithmetic, integers wrap around
+	testBooleanObject(testing, testEvaluate(testing, "9223372036854775807 + 1 == -9223372036854775807 - 1;"), true)
+}
+
+func TestStringInterpolation(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let name = "Alice"; "Hello ${name}";`, "Hello Alice"},
+		{`let age = 41; "you are ${age + 1}!";`, "you are 42!"},
+		{`"${1}${2.5}${true}${[1, 2]}";`, "12.5true[1, 2]"},
+		{`let inner = "b"; "a ${"[${inner}]"} c";`, "a [b] c"},
+		{`"price: \${amount}";`, "price: ${amount}"},
+		{`"no interpolation";`, "no interpolation"},
+	}
+
+	for _, test := range tests {
+		testStringObject(testing, testEvaluate(testing, test.input), test.expected)
+	}
+}
+
+func TestStringInterpolationErrors(testing *testing.T) {
+	tests := []struct {
+		input            string
+		expected         string
+		expectedPosition int
+	}{
+		{`"value: ${missing}";`, "identifier not found: missing", 10},
+		{`let x = 1;  "a ${x} b ${x + true}";`, "type mismatch: INTEGER + BOOLEAN", 26},
+		{`"a ${"b ${missing}"}";`, "identifier not found: missing", 10},
+	}
+
+	for _, test := range tests {
+		result := testEvaluate(testing, test.input)
+		testErrorObject(testing, result, test.expected)
+
+		// Errors point inside the string, where the interpolated expression failed
+		if err, ok := result.(*object.Error); ok && (err.Line != 1 || err.Position != test.expectedPosition) {
+			testing.Errorf("wrong location for %q. expected=l.1:p.%d, got=l.%d:p.%d", test.input, test.expectedPosition, err.Line, err.Position)
+		}
+	}
+}
+
+func TestUnicodeIdentifiers(testing *testing.T) {
+	input := `let prénom = "Zoé"; let 名前 = "名"; let user1 = "${prénom} ${名前}"; user1;`
+	testStringObject(testing, testEvaluate(testing, input), "Zoé 名")
+}
+
+func TestIntegerLiterals(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"0xFF;", 255},
+		{"0Xff;", 255},
+		{"0b1010;", 10},
+		{"0o755;", 493},
+		{"1_000_000;", 1000000},
+		{"0xFF_FF;", 65535},
+		{"0b1111_0000;", 240},
+		{"9223372036854775807;", 9223372036854775807},
+		{"010;", 10},
+		{"09;", 9},
+		{"00_7;", 7},
+	}
+
+	for _, test := range tests {
+		testIntegerObject(testing, testEvaluate(testing, test.input), test.expected)
+	}
+
+	testFloatObject(testing, testEvaluate(testing, "1_000.5;"), 1000.5)
+}
+
+func TestLoopErrors(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"while (true) { 1 + true; };", "type mismatch: INTEGER + BOOLEAN"},
+		{"for (x in 5) { x; };", "cannot iterate over INTEGER"},
+	}
+
+	for _, test := range tests {
+		testErrorObject(testing, testEvaluate(testing, test.input), test.expected)
+	}
+}
+
+// Utils
+
+func testEvaluate(testing *testing.T, input string) object.Object {
+	return testEvaluateProgram(testing, input, object.NewProgramEnvironment(""))
+}
+
+func testEvaluateProgram(
+	testing *testing.T,
+	input string,
+	programEnvironment *object.ProgramEnvironment,
+) object.Object {
+	return testEvaluateFile(testing, input, "", programEnvironment)
+}
+
+func testEvaluateFile(
+	testing *testing.T,
+	input string,
+	filepath string,
+	programEnvironment *object.ProgramEnvironment,
+) object.Object {
+	lexer := lexer.New(input, func() (string, bool) {
+		return "", true
+	})
+
+	parser := parser.New(lexer)
+	program := parser.ParseProgram()
+
+	errors := parser.GetErrors()
+	if len(errors) > 0 {
+		for _, err := range errors {
+			testing.Errorf("parser error: %q", err)
+		}
+
+		testing.FailNow()
+	}
+
+	environment := object.NewEnvironment(filepath, programEnvironment)
+	return evaluator.Evaluate(program, environment)
+}
+
+func testIntegerObject(testing *testing.T, evaluated object.Object, expected int64) bool {
+	result, ok := evaluated.(*object.Integer)
+	if !ok {
+		testing.Errorf("object is not Integer. got=%T (%+v)", evaluated, evaluated)
+		return false
+	}
+
+	if result.Value != expected {
+		testing.Errorf("object has wrong value. got=%d, want=%d", result.Value, expected)
+		return false
+	}
+
+	return true
+}
+
+func testStringObject(testing *testing.T, evaluated object.Object, expected string) bool {
+	result, ok := evaluated.(*object.String)
+	if !ok {
+		testing.Errorf("object is not String. got=%T (%+v)", evaluated, evaluated)
+		return false
+	}
+
+	if result.Value != expected {
+		testing.Errorf("object has wrong value. got=%q, want=%q", result.Value, expected)
+		return false
+	}
+
+	return true
+}
+
+func testFloatObject(testing *testing.T, evaluated object.Object, expected float64) bool {
+	result, ok := evaluated.(*object.Float)
+	if !ok {
+		testing.Errorf("object is not Float. got=%T (%+v)", evaluated, evaluated)
+		return false
+	}
+
+	if result.Value != expected {
+		testing.Errorf("object has wrong value. got=%g, want=%g", result.Value, expected)
+		return false
+	}
+
+	return true
+}
+
+func testBooleanObject(testing *testing.T, evaluated object.Object, expected bool) bool {
+	result, ok := evaluated.(*object.Boolean)
+	if !ok {
+		testing.Errorf("object is not Boolean. got=%T (%+v)", evaluated, evaluated)
+		return false
+	}
+
+	if result.Value != expected {
+		testing.Errorf("object has wrong value. got=%t, want=%t", result.Value, expected)
+		return false
+	}
+
+	return true
+}
+
+func testNullObject(testing *testing.T, evaluated object.Object) bool {
+	if evaluated != evaluator.NULL {
+		testing.Errorf("object is not NULL. got=%T (%+v)", evaluated, evaluated)
+		return false
+	}
+
+	return true
+}
+
+func testErrorObject(testing *testing.T, evaluated object.Object, expected string) bool {
+	result, ok := evaluated.(*object.Error)
+	if !ok {
+		testing.Errorf("object is not Error. got=%T (%+v)", evaluated, evaluated)
+		return false
+	}
+
+	if result.Message != expected {
+		testing.Errorf("wrong error message. got=%q, want=%q", result.Message, expected)
+		return false
+	}
+
+	return true
+}
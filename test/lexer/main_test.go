@@ -27,7 +27,7 @@ import (
 // 		10 != 9;
 // 	`
 
-var input = `let five = 5;`
+var input = `let five = 5; five != 4 <= 3 >= 2 % 1 ** 0; 1 & 2 | 3 ^ ~4 << 5 >> 6; a?.b ?? null; c ? 1 : 2; match (c) { _ => c }; [...a.b];`
 
 func TestNextToken(t *testing.T) {
 
@@ -40,6 +40,61 @@ func TestNextToken(t *testing.T) {
 		{token.ASSIGN, "="},
 		{token.INT, "5"},
 		{token.SEMICOLON, ";"},
+		{token.IDENTIFIER, "five"},
+		{token.NOT_EQUALS, "!="},
+		{token.INT, "4"},
+		{token.LESS_EQUALS, "<="},
+		{token.INT, "3"},
+		{token.GREATER_EQUALS, ">="},
+		{token.INT, "2"},
+		{token.PERCENT, "%"},
+		{token.INT, "1"},
+		{token.POWER, "**"},
+		{token.INT, "0"},
+		{token.SEMICOLON, ";"},
+		{token.INT, "1"},
+		{token.AMPERSAND, "&"},
+		{token.INT, "2"},
+		{token.PIPE, "|"},
+		{token.INT, "3"},
+		{token.CARET, "^"},
+		{token.TILDE, "~"},
+		{token.INT, "4"},
+		{token.SHIFT_LEFT, "<<"},
+		{token.INT, "5"},
+		{token.SHIFT_RIGHT, ">>"},
+		{token.INT, "6"},
+		{token.SEMICOLON, ";"},
+		{token.IDENTIFIER, "a"},
+		{token.QUESTION_DOT, "?."},
+		{token.IDENTIFIER, "b"},
+		{token.NULLISH, "??"},
+		{token.NULL, "null"},
+		{token.SEMICOLON, ";"},
+		{token.IDENTIFIER, "c"},
+		{token.QUESTION, "?"},
+		{token.INT, "1"},
+		{token.COLON, ":"},
+		{token.INT, "2"},
+		{token.SEMICOLON, ";"},
+		{token.MATCH, "match"},
+		{token.LPAREN, "("},
+		{token.IDENTIFIER, "c"},
+		{token.RPAREN, ")"},
+		{token.LBRACE, "{"},
+		{token.IDENTIFIER, "_"},
+		{token.FAT_ARROW, "=>"},
+		{token.IDENTIFIER, "c"},
+		{token.RBRACE, "}"},
+		{token.SEMICOLON, ";"},
+		{token.LBRACKET, "["},
+		{token.ELLIPSIS, "..."},
+		{token.IDENTIFIER, "a"},
+		{token.DOT, "."},
+		{token.IDENTIFIER, "b"},
+		{token.RBRACKET, "]"},
+		{token.SEMICOLON, ";"},
+		{token.EOF, ""},
 		// {token.LET, "let"},
 		// {token.IDENT, "ten"},
 		// {token.ASSIGN, "="},
@@ -73,7 +128,9 @@ func TestNextToken(t *testing.T) {
 		// {token.SEMICOLON, ";"},
 	}
 
-	lexer := lexer.New(input)
+	lexer := lexer.New(input, func() (string, bool) {
+		return "", true
+	})
 
 	for index, test := range tests {
 		tok := lexer.Next()
@@ -89,3 +146,194 @@ func TestNextToken(t *testing.T) {
 		}
 	}
 }
+
+func TestComments(t *testing.T) {
+	lines := []string{
+		"let x = 5; // trailing comment",
+		"/* block comment",
+		"   spanning lines */ let y = x / 2;",
+		"// last line comment",
+	}
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+		expectedLine    int
+	}{
+		{token.LET, "let", 1},
+		{token.IDENTIFIER, "x", 1},
+		{token.ASSIGN, "=", 1},
+		{token.INT, "5", 1},
+		{token.SEMICOLON, ";", 1},
+		{token.COMMENT, "// trailing comment", 1},
+		{token.COMMENT, "/* block comment\n   spanning lines */", 2},
+		{token.LET, "let", 3},
+		{token.IDENTIFIER, "y", 3},
+		{token.ASSIGN, "=", 3},
+		{token.IDENTIFIER, "x", 3},
+		{token.SLASH, "/", 3},
+		{token.INT, "2", 3},
+		{token.SEMICOLON, ";", 3},
+		{token.COMMENT, "// last line comment", 4},
+		{token.EOF, "", 4},
+	}
+
+	lexer := newLinesLexer(lines)
+	lexer.SetKeepComments(true)
+
+	for index, test := range tests {
+		tok := lexer.Next()
+
+		if tok.Type != test.expectedType || tok.Literal != test.expectedLiteral || tok.Line != test.expectedLine {
+			t.Fatalf("tests[%d] - wrong token. expected=%q %q l.%d, got=%q %q l.%d",
+				index, test.expectedType, test.expectedLiteral, test.expectedLine, tok.Type, tok.Literal, tok.Line)
+		}
+	}
+
+	// Comments are skipped by default
+	lexer = newLinesLexer(lines)
+	for _, expectedType := range []token.TokenType{token.LET, token.IDENTIFIER, token.ASSIGN, token.INT, token.SEMICOLON, token.LET} {
+		tok := lexer.Next()
+		if tok.Type != expectedType {
+			t.Fatalf("tokentype wrong. expected=%q, got=%q", expectedType, tok.Type)
+		}
+	}
+}
+
+func TestUnterminatedBlockComment(t *testing.T) {
+	lexer := newLinesLexer([]string{"let x = 5; /* never", "closed"})
+
+	for range 5 {
+		lexer.Next()
+	}
+
+	tok := lexer.Next()
+	if tok.Type != token.ILLEGAL {
+		t.Fatalf("tokentype wrong. expected=%q, got=%q", token.ILLEGAL, tok.Type)
+	}
+}
+
+func newLinesLexer(lines []string) *lexer.Lexer {
+	index := 0
+	return lexer.New(lines[0], func() (string, bool) {
+		index++
+		if index >= len(lines) {
+			return "", true
+		}
+
+		return lines[index], false
+	})
+}
+
+func TestStrings(t *testing.T) {
+	lines := []string{
+		`"tab\there" "quote \" and \\ backslash" "é\u{1F600}"`,
+		"`raw \\n string",
+		"",
+		"spanning lines` \"unterminated",
+		`"bad \q escape";`,
+	}
+
+	tests := []struct {
+		expectedType     token.TokenType
+		expectedLiteral  string
+		expectedLine     int
+		expectedPosition int
+	}{
+		{token.STRING, "tab\there", 1, 0},
+		{token.STRING, "quote \" and \\ backslash", 1, 12},
+		{token.STRING, "é😀", 1, 40},
+		{token.STRING, "raw \\n string\n\nspanning lines", 2, 0},
+		{token.ILLEGAL, "\"unterminated", 4, 16},
+		{token.ILLEGAL, "\\q", 5, 5},
+		{token.SEMICOLON, ";", 5, 15},
+		{token.EOF, "", 5, 16},
+	}
+
+	lexer := newLinesLexer(lines)
+
+	for index, test := range tests {
+		tok := lexer.Next()
+
+		if tok.Type != test.expectedType ||
+			tok.Literal != test.expectedLiteral ||
+			tok.Line != test.expectedLine ||
+			tok.Position != test.expectedPosition {
+			t.Fatalf("tests[%d] - wrong token. expected=%q %q l.%d:p.%d, got=%q %q l.%d:p.%d",
+				index,
+				test.expectedType, test.expectedLiteral, test.expectedLine, test.expectedPosition,
+				tok.Type, tok.Literal, tok.Line, tok.Position)
+		}
+	}
+}
+
+func TestStringInterpolation(t *testing.T) {
+	input := `"Hello ${name}, in ${ {"a": "${x}"}["a"] } \${escaped}!"`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.TEMPLATE_HEAD, "Hello "},
+		{token.IDENTIFIER, "name"},
+		{token.TEMPLATE_MIDDLE, ", in "},
+		{token.LBRACE, "{"},
+		{token.STRING, "a"},
+		{token.COLON, ":"},
+		{token.TEMPLATE_HEAD, ""},
+		{token.IDENTIFIER, "x"},
+		{token.TEMPLATE_TAIL, ""},
+		{token.RBRACE, "}"},
+		{token.LBRACKET, "["},
+		{token.STRING, "a"},
+		{token.RBRACKET, "]"},
+		{token.TEMPLATE_TAIL, " ${escaped}!"},
+		{token.EOF, ""},
+	}
+
+	lexer := newLinesLexer([]string{input})
+
+	for index, test := range tests {
+		tok := lexer.Next()
+
+		if tok.Type != test.expectedType || tok.Literal != test.expectedLiteral {
+			t.Fatalf("tests[%d] - wrong token. expected=%q %q, got=%q %q",
+				index, test.expectedType, test.expectedLiteral, tok.Type, tok.Literal)
+		}
+	}
+}
+
+func TestUnicodeIdentifiers(t *testing.T) {
+	input := `let prénom = "Zoé"; let 名前 = user1 + _x2;`
+
+	tests := []struct {
+		expectedType     token.TokenType
+		expectedLiteral  string
+		expectedPosition int
+	}{
+		{token.LET, "let", 0},
+		{token.IDENTIFIER, "prénom", 4},
+		{token.ASSIGN, "=", 11},
+		{token.STRING, "Zoé", 13},
+		{token.SEMICOLON, ";", 18},
+		{token.LET, "let", 20},
+		{token.IDENTIFIER, "名前", 24},
+		{token.ASSIGN, "=", 27},
+		{token.IDENTIFIER, "user1", 29},
+		{token.PLUS, "+", 35},
+		{token.IDENTIFIER, "_x2", 37},
+		{token.SEMICOLON, ";", 40},
+		{token.EOF, "", 41},
+	}
+
+	lexer := newLinesLexer([]string{input})
+
+	for index, test := range tests {
+		tok := lexer.Next()
+
+		if tok.Type != test.expectedType || tok.Literal != test.expectedLiteral || tok.Position != test.expectedPosition {
+			t.Fatalf("tests[%d] - wrong token. expected=%q %q p.%d, got=%q %q p.%d",
+				index, test.expectedType, test.expectedLiteral, test.expectedPosition, tok.Type, tok.Literal, tok.Position)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package parser_test
 
 import (
 	"glass/language/ast"
+	"glass/language/diagnostic"
 	lexer "glass/language/lexer"
 	parser "glass/language/parser"
 	"testing"
@@ -19,8 +20,7 @@ func TestLetStatements(testing *testing.T) {
 	}
 
 	for _, test := range tests {
-		lexer := lexer.New(test.input)
-		parser := parser.New(lexer)
+		parser := parser.New(newLexer(test.input))
 		program := parser.ParseProgram()
 		checkParserErrors(testing, parser)
 
@@ -55,19 +55,669 @@ func testLetStatement(testing *testing.T, statement ast.Statement, name string)
 		return false
 	}
 
-	if letStatement.Identifier.Value != name {
-		testing.Errorf("letStatement.Name.Value not '%s'. got=%s", name, letStatement.Identifier.Value)
+	identifier, ok := letStatement.Pattern.(*ast.Identifier)
+	if !ok {
+		testing.Errorf("letStatement.Pattern not *ast.Identifier. got=%T", letStatement.Pattern)
 		return false
 	}
 
-	if letStatement.Identifier.TokenLiteral() != name {
-		testing.Errorf("s.Name not '%s'. got=%s", name, letStatement.Identifier)
+	if identifier.Value != name {
+		testing.Errorf("letStatement.Name.Value not '%s'. got=%s", name, identifier.Value)
+		return false
+	}
+
+	if identifier.TokenLiteral() != name {
+		testing.Errorf("s.Name not '%s'. got=%s", name, identifier)
 		return false
 	}
 
 	return true
 }
 
+func TestHashLiterals(testing *testing.T) {
+	tests := []struct {
+		input        string
+		expectedKeys []string
+	}{
+		{"{};", []string{}},
+		{"{\"a\": 1};", []string{"a"}},
+		{"{\"a\": 1, \"b\": 2 + 3};", []string{"a", "b"}},
+		{"{\"a\": 1, \"b\": {\"c\": 2}, \"d\": 4,};", []string{"a", "b", "d"}},
+	}
+
+	for _, test := range tests {
+		parser := parser.New(newLexer(test.input))
+		program := parser.ParseProgram()
+		checkParserErrors(testing, parser)
+
+		if len(program.Statements) != 1 {
+			testing.Fatalf("program.Statements does not contain 1 statement. got=%d", len(program.Statements))
+		}
+
+		statement := program.Statements[0].(*ast.ExpressionStatement)
+		hash, ok := statement.Expression.(*ast.HashLiteral)
+		if !ok {
+			testing.Fatalf("expression is not ast.HashLiteral. got=%T", statement.Expression)
+		}
+
+		if len(hash.Pairs) != len(test.expectedKeys) {
+			testing.Errorf("wrong number of pairs for %q. expected=%d, got=%d", test.input, len(test.expectedKeys), len(hash.Pairs))
+			continue
+		}
+
+		for _, expectedKey := range test.expectedKeys {
+			found := false
+			for key := range hash.Pairs {
+				if key.String() == expectedKey {
+					found = true
+				}
+			}
+
+			if !found {
+				testing.Errorf("missing key %q in %q", expectedKey, test.input)
+			}
+		}
+	}
+}
+
+func TestUnexpectedTokenErrors(testing *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"let x 5;", "Expected token =, got INT instead (l.1:p.6)"},
+		{"let 5 = x;", "Expected token IDENTIFIER, got INT instead (l.1:p.4)"},
+		{"if (x { 1 };", "Expected token ), got { instead (l.1:p.6)"},
+		{"[1, 2;", "Expected token ], got ; instead (l.1:p.5)"},
+	}
+
+	for _, test := range tests {
+		parser := parser.New(newLexer(test.input))
+		parser.ParseProgram()
+
+		errors := parser.GetErrors()
+		if len(errors) == 0 || errors[0] != test.expectedError {
+			testing.Errorf("wrong parser errors for %q. got=%q, want first=%q", test.input, errors, test.expectedError)
+		}
+	}
+}
+
+func TestAccessExpressions(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"math.pi;", "(math).(pi)"},
+		{"math.pi * 2;", "((math).(pi) * 2)"},
+		{"math.add(1, 2);", "(math).(add)(1, 2)"},
+		{"config.server.port;", "((config).(server)).(port)"},
+	}
+
+	for _, test := range tests {
+		parser := parser.New(newLexer(test.input))
+		program := parser.ParseProgram()
+		checkParserErrors(testing, parser)
+
+		if program.String() != test.expected {
+			testing.Errorf("wrong program for %q. expected=%q, got=%q", test.input, test.expected, program.String())
+		}
+	}
+}
+
+func TestDestructuringParsing(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let [a, b] = x;", "let [a, b] = x;"},
+		{"let [a, ...rest] = x;", "let [a, ...rest] = x;"},
+		{"let [...all] = x;", "let [...all] = x;"},
+		{"let {name, \"home\": [city, _]} = x;", "let {name:name, home:[city, _]} = x;"},
+		{"let {id, ...others} = x;", "let {id:id, ...others} = x;"},
+		{"let f = fn([a, b], {c}, d) { a };", "let f = fn([a, b], {c:c}, d) a;"},
+	}
+
+	for _, test := range tests {
+		parser := parser.New(newLexer(test.input))
+		program := parser.ParseProgram()
+		checkParserErrors(testing, parser)
+
+		if program.String() != test.expected {
+			testing.Errorf("wrong parsing for %q. got=%q, want=%q", test.input, program.String(), test.expected)
+		}
+	}
+}
+
+func TestDestructuringErrors(testing *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"let 5 = x;", "Expected token IDENTIFIER, got INT instead (l.1:p.4)"},
+		{"let [a, ...rest, b] = x;", "Expected token ], got , instead (l.1:p.15)"},
+		{"let [a, ...] = x;", "Expected token IDENTIFIER, got ] instead (l.1:p.11)"},
+		{"let {...rest, a} = x;", "Expected token }, got , instead (l.1:p.12)"},
+		{"let {null: a} = x;", "Invalid pattern \"null\" (l.1:p.5)"},
+		{"let f = fn(a, 1) { a };", "Expected token IDENTIFIER, got INT instead (l.1:p.14)"},
+	}
+
+	for _, test := range tests {
+		parser := parser.New(newLexer(test.input))
+		parser.ParseProgram()
+
+		errors := parser.GetErrors()
+		if len(errors) == 0 || errors[0] != test.expectedError {
+			testing.Errorf("wrong parser errors for %q. got=%q, want first=%q", test.input, errors, test.expectedError)
+		}
+	}
+}
+
+func TestFunctionParameterParsing(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let f = fn(a, b = 2) { a };", "let f = fn(a, b = 2) a;"},
+		{"let f = fn(a, ...rest) { a };", "let f = fn(a, ...rest) a;"},
+		{"let f = fn([a, b] = [1, 2]) { a };", "let f = fn([a, b] = [1, 2]) a;"},
+		{"f(...values, 1);", "f(...values, 1)"},
+		{"f(1, b: 2, c: x + 1);", "f(1, b: 2, c: (x + 1))"},
+		{"f(a ? b : c);", "f((a ? b : c))"},
+	}
+
+	for _, test := range tests {
+		parser := parser.New(newLexer(test.input))
+		program := parser.ParseProgram()
+		checkParserErrors(testing, parser)
+
+		if program.String() != test.expected {
+			testing.Errorf("wrong parsing for %q. got=%q, want=%q", test.input, program.String(), test.expected)
+		}
+	}
+}
+
+func TestFunctionParameterErrors(testing *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"let f = fn(...rest, a) { a };", "Expected token ), got , instead (l.1:p.18)"},
+		{"let f = fn(...) { 1 };", "Expected token IDENTIFIER, got ) instead (l.1:p.14)"},
+		{"f(a: 1, 2);", "Positional argument 2 after named arguments (l.1:p.8)"},
+		{"f(1 2);", "Expected token ,, got INT instead (l.1:p.4)"},
+	}
+
+	for _, test := range tests {
+		parser := parser.New(newLexer(test.input))
+		parser.ParseProgram()
+
+		errors := parser.GetErrors()
+		if len(errors) == 0 || errors[0] != test.expectedError {
+			testing.Errorf("wrong parser errors for %q. got=%q, want first=%q", test.input, errors, test.expectedError)
+		}
+	}
+}
+
+func TestTryStatementParsing(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"try { a } catch (e) { b }", "try { a } catch (e) { b }"},
+		{"try { a } catch { b } finally { c }", "try { a } catch { b } finally { c }"},
+		{"try { a } finally { c };", "try { a } finally { c }"},
+		{"throw error(\"a\");", "throw error(a);"},
+	}
+
+	for _, test := range tests {
+		parser := parser.New(newLexer(test.input))
+		program := parser.ParseProgram()
+		checkParserErrors(testing, parser)
+
+		if program.String() != test.expected {
+			testing.Errorf("wrong parsing for %q. got=%q, want=%q", test.input, program.String(), test.expected)
+		}
+	}
+}
+
+func TestTryStatementErrors(testing *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"try { a };", "try statement without catch or finally (l.1:p.0)"},
+		{"try { a } catch (1) { b }", "Expected token IDENTIFIER, got INT instead (l.1:p.17)"},
+		{"try a catch { b }", "Expected token {, got IDENTIFIER instead (l.1:p.4)"},
+		{"throw;", "no prefix parse function found for \";\" token (l.1:p.5)"},
+	}
+
+	for _, test := range tests {
+		parser := parser.New(newLexer(test.input))
+		parser.ParseProgram()
+
+		errors := parser.GetErrors()
+		if len(errors) == 0 || errors[0] != test.expectedError {
+			testing.Errorf("wrong parser errors for %q. got=%q, want first=%q", test.input, errors, test.expectedError)
+		}
+	}
+}
+
+func TestLoopStatements(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"while (x < 3) { x += 1; };", "while ((x < 3)) { (x += 1) }"},
+		{"for (let i = 0; i < 3; i += 1) { x; };", "for (let i = 0; (i < 3); (i += 1)) { x }"},
+		{"for (let i = 0;; i += 1) { x; };", "for (let i = 0; ; (i += 1)) { x }"},
+		{"for (let i = 0; i < 3;) { x; };", "for (let i = 0; (i < 3); ) { x }"},
+		{"for (;;) { x; };", "for (; ; ) { x }"},
+		{"outer: for (x in [1, 2]) { y; };", "outer: for (x in [1, 2]) { y }"},
+	}
+
+	for _, test := range tests {
+		programParser := parser.New(newLexer(test.input))
+		program := programParser.ParseProgram()
+		checkParserErrors(testing, programParser)
+
+		if program.String() != test.expected {
+			testing.Errorf("wrong program for %q. expected=%q, got=%q", test.input, test.expected, program.String())
+		}
+
+		// Printed loops parse back to themselves
+		reparser := parser.New(newLexer(program.String()))
+		reparsed := reparser.ParseProgram()
+		checkParserErrors(testing, reparser)
+
+		if reparsed.String() != test.expected {
+			testing.Errorf("printed loop does not parse back. expected=%q, got=%q", test.expected, reparsed.String())
+		}
+	}
+}
+
+func TestLoopControlErrors(testing *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"break;", "break statement outside of a loop (l.1:p.0)"},
+		{"while (true) { let f = fn() { continue; }; };", "continue statement outside of a loop (l.1:p.30)"},
+		{"outer: while (true) { break inner; };", "Unknown loop label \"inner\" (l.1:p.28)"},
+		{"outer: let x = 5;", "Label \"outer\" must be followed by a loop (l.1:p.0)"},
+	}
+
+	for _, test := range tests {
+		parser := parser.New(newLexer(test.input))
+		parser.ParseProgram()
+
+		errors := parser.GetErrors()
+		if len(errors) == 0 || errors[0] != test.expectedError {
+			testing.Errorf("wrong parser errors for %q. got=%q, want first=%q", test.input, errors, test.expectedError)
+		}
+	}
+}
+
+func TestAssignmentErrors(testing *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"5 = 6;", "Invalid assignment target 5 (l.1:p.2)"},
+		{"let x = 1; x + 1 = 6;", "Invalid assignment target (x + 1) (l.1:p.17)"},
+
+		// Targets left incomplete by an earlier error
+		{"a + if x = 1", "Expected token (, got IDENTIFIER instead (l.1:p.7)"},
+		{"a && if x = 1", "Expected token (, got IDENTIFIER instead (l.1:p.8)"},
+		{"!if x = 1", "Expected token (, got IDENTIFIER instead (l.1:p.4)"},
+		{"let v = a < match x = 2;", "Expected token (, got IDENTIFIER instead (l.1:p.18)"},
+	}
+
+	for _, test := range tests {
+		parser := parser.New(newLexer(test.input))
+		parser.ParseProgram()
+
+		errors := parser.GetErrors()
+		if len(errors) == 0 || errors[0] != test.expectedError {
+			testing.Errorf("wrong parser errors for %q. got=%q, want first=%q", test.input, errors, test.expectedError)
+		}
+	}
+}
+
+func TestStringErrors(testing *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"let x = \"never closed;", "Unterminated string (l.1:p.8)"},
+		{"let x = `never closed;", "Unterminated string (l.1:p.8)"},
+		{"let x = \"a \\z b\";", "Invalid escape sequence \"\\\\z\" in string (l.1:p.11)"},
+		{"let x = 1; /* never closed", "Unterminated block comment (l.1:p.11)"},
+		{"let x = \"a ${1} never closed;", "Unterminated string (l.1:p.14)"},
+		{"let x = \\n;", "Illegal token \"\\\\\" found (l.1:p.8)"},
+		{"let x = #;", "Illegal token \"#\" found (l.1:p.8)"},
+		{"let x = \"a ${} b\";", "Empty string interpolation (l.1:p.13)"},
+		{"let x = \"a ${1 +} b\";", "no prefix parse function found for \"TEMPLATE_TAIL\" token (l.1:p.16)"},
+	}
+
+	for _, test := range tests {
+		parser := parser.New(newLexer(test.input))
+		parser.ParseProgram()
+
+		errors := parser.GetErrors()
+		if len(errors) == 0 || errors[0] != test.expectedError {
+			testing.Errorf("wrong parser errors for %q. got=%q, want first=%q", test.input, errors, test.expectedError)
+		}
+	}
+}
+
+func TestIntegerLiteralErrors(testing *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"let x = 9223372036854775808;", "integer literal 9223372036854775808 out of range (l.1:p.8)"},
+		{"let x = 0x1_0000_0000_0000_0000;", "integer literal 0x1_0000_0000_0000_0000 out of range (l.1:p.8)"},
+		{"let x = 0b102;", "could not parse \"0b102\" as integer (l.1:p.8)"},
+		{"let x = 0xG;", "could not parse \"0xG\" as integer (l.1:p.8)"},
+		{"let x = 1__0;", "could not parse \"1__0\" as integer (l.1:p.8)"},
+		{"let x = 10_;", "could not parse \"10_\" as integer (l.1:p.8)"},
+	}
+
+	for _, test := range tests {
+		parser := parser.New(newLexer(test.input))
+		parser.ParseProgram()
+
+		errors := parser.GetErrors()
+		if len(errors) == 0 || errors[0] != test.expectedError {
+			testing.Errorf("wrong parser errors for %q. got=%q, want first=%q", test.input, errors, test.expectedError)
+		}
+	}
+}
+
+func TestConditionalExpressionParsing(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"a ? b : c;", "(a ? b : c)"},
+		{"a ? b : c ? d : e;", "(a ? b : (c ? d : e))"},
+		{"a ? b ? c : d : e;", "(a ? (b ? c : d) : e)"},
+		{"a || b ? c + 1 : d * 2;", "((a || b) ? (c + 1) : (d * 2))"},
+		{"a ?? b ? c : d;", "((a ?? b) ? c : d)"},
+		{"x = a ? b : c;", "(x = (a ? b : c))"},
+	}
+
+	for _, test := range tests {
+		parser := parser.New(newLexer(test.input))
+		program := parser.ParseProgram()
+		checkParserErrors(testing, parser)
+
+		if program.String() != test.expected {
+			testing.Errorf("wrong parsing for %q. got=%q, want=%q", test.input, program.String(), test.expected)
+		}
+	}
+}
+
+func TestIfExpressionParsing(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"if (a) { b }", "if (a) { b }"},
+		{"if (a) { b } else { c }", "if (a) { b } else { c }"},
+		{"if (a) { b } else if (c > 1) { d }", "if (a) { b } else if ((c > 1)) { d }"},
+		{"if (a) { b } else if (c) { d } else if (e) { f } else { g }", "if (a) { b } else if (c) { d } else if (e) { f } else { g }"},
+	}
+
+	for _, test := range tests {
+		parser := parser.New(newLexer(test.input))
+		program := parser.ParseProgram()
+		checkParserErrors(testing, parser)
+
+		if program.String() != test.expected {
+			testing.Errorf("wrong parsing for %q. got=%q, want=%q", test.input, program.String(), test.expected)
+		}
+	}
+}
+
+func TestMatchExpressionParsing(testing *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"match (x) { 1 => a, _ => b };", "match (x) { 1 => a, _ => b }"},
+		{"match (x) { -1 => a, };", "match (x) { (-1) => a }"},
+		{"match (x) { [a, [b]] => a + b };", "match (x) { [a, [b]] => (a + b) }"},
+		{"match (x) { {\"type\": t, size} => t };", "match (x) { {type:t, size:size} => t }"},
+		{"match (x) { 1 => { a } 2 => { b } };", "match (x) { 1 => { a }, 2 => { b } }"},
+	}
+
+	for _, test := range tests {
+		parser := parser.New(newLexer(test.input))
+		program := parser.ParseProgram()
+		checkParserErrors(testing, parser)
+
+		if program.String() != test.expected {
+			testing.Errorf("wrong parsing for %q. got=%q, want=%q", test.input, program.String(), test.expected)
+		}
+	}
+}
+
+func TestMatchExpressionErrors(testing *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"match (x) { 1 + 2 => a };", "Expected token =>, got + instead (l.1:p.14)"},
+		{"match (x) { f() => a };", "Expected token =>, got ( instead (l.1:p.13)"},
+		{"match (x) { -a => b };", "Invalid pattern \"-\" (l.1:p.12)"},
+		{"match (x) { 1 => a 2 => b };", "Expected token ,, got INT instead (l.1:p.19)"},
+		{"match (x) { [a b] => a };", "Expected token ,, got IDENTIFIER instead (l.1:p.15)"},
+		{"match (x) { {[a]: b} => b };", "Invalid pattern \"[\" (l.1:p.13)"},
+	}
+
+	for _, test := range tests {
+		parser := parser.New(newLexer(test.input))
+		parser.ParseProgram()
+
+		errors := parser.GetErrors()
+		if len(errors) == 0 || errors[0] != test.expectedError {
+			testing.Errorf("wrong parser errors for %q. got=%q, want first=%q", test.input, errors, test.expectedError)
+		}
+	}
+}
+
+func TestAccessErrors(testing *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"config.5;", "Expected token IDENTIFIER, got INT instead (l.1:p.7)"},
+		{"config?.;", "Expected token IDENTIFIER, got ; instead (l.1:p.8)"},
+		{"values?.[0;", "Expected token ], got ; instead (l.1:p.10)"},
+	}
+
+	for _, test := range tests {
+		parser := parser.New(newLexer(test.input))
+		parser.ParseProgram()
+
+		errors := parser.GetErrors()
+		if len(errors) == 0 || errors[0] != test.expectedError {
+			testing.Errorf("wrong parser errors for %q. got=%q, want first=%q", test.input, errors, test.expectedError)
+		}
+	}
+}
+
+func TestErrorRecovery(testing *testing.T) {
+	tests := []struct {
+		input              string
+		expectedErrors     []string
+		expectedStatements int
+	}{
+		{
+			"let x = (1 + 2; let y = 3;",
+			[]string{"Expected token ), got ; instead (l.1:p.14)"},
+			1,
+		},
+		{
+			"let x = 1; let = 2; let y = 3;",
+			[]string{"Expected token IDENTIFIER, got = instead (l.1:p.15)"},
+			2,
+		},
+		{
+			"} let x = 1;",
+			[]string{"no prefix parse function found for \"}\" token (l.1:p.0)"},
+			1,
+		},
+		{
+			"let a = 0x; let b = 1 +; let c = 3;",
+			[]string{
+				"could not parse \"0x\" as integer (l.1:p.8)",
+				"no prefix parse function found for \";\" token (l.1:p.23)",
+			},
+			1,
+		},
+		{
+			"let = 5; let y = [1, 2; print(y);",
+			[]string{
+				"Expected token IDENTIFIER, got = instead (l.1:p.4)",
+				"Expected token ], got ; instead (l.1:p.22)",
+			},
+			1,
+		},
+		{
+			"let f = fn() { 1 + }; let y = ;",
+			[]string{
+				"no prefix parse function found for \"}\" token (l.1:p.19)",
+				"no prefix parse function found for \";\" token (l.1:p.30)",
+			},
+			1,
+		},
+		{
+			"let h = {\"a\": 1 +}; let z = 2 * ;",
+			[]string{
+				"no prefix parse function found for \"}\" token (l.1:p.17)",
+				"no prefix parse function found for \";\" token (l.1:p.32)",
+			},
+			0,
+		},
+		{
+			"while (true { break; }; let a = 1 +;",
+			[]string{
+				"Expected token ), got { instead (l.1:p.12)",
+				"no prefix parse function found for \";\" token (l.1:p.35)",
+			},
+			0,
+		},
+		{
+			"if (x) { let = 1; } else { let b = ; };",
+			[]string{
+				"Expected token IDENTIFIER, got = instead (l.1:p.13)",
+				"no prefix parse function found for \";\" token (l.1:p.35)",
+			},
+			1,
+		},
+		{
+			"let f = fn() { let a = ; let b = 2; return a +; }; f(;",
+			[]string{
+				"no prefix parse function found for \";\" token (l.1:p.23)",
+				"no prefix parse function found for \";\" token (l.1:p.46)",
+				"no prefix parse function found for \";\" token (l.1:p.53)",
+			},
+			1,
+		},
+		{
+			"for (x in) { continue; }; let y = \"abc",
+			[]string{
+				"no prefix parse function found for \")\" token (l.1:p.9)",
+				"Unterminated string (l.1:p.34)",
+			},
+			0,
+		},
+	}
+
+	for _, test := range tests {
+		parser := parser.New(newLexer(test.input))
+		program := parser.ParseProgram()
+
+		errors := parser.GetErrors()
+		if len(errors) != len(test.expectedErrors) {
+			testing.Errorf("wrong parser errors for %q. got=%q, want=%q", test.input, errors, test.expectedErrors)
+			continue
+		}
+
+		for i, expected := range test.expectedErrors {
+			if errors[i] != expected {
+				testing.Errorf("wrong parser errors for %q. got=%q, want=%q", test.input, errors, test.expectedErrors)
+				break
+			}
+		}
+
+		if len(program.Statements) != test.expectedStatements {
+			testing.Errorf(
+				"wrong number of statements for %q. expected=%d, got=%d",
+				test.input,
+				test.expectedStatements,
+				len(program.Statements),
+			)
+		}
+	}
+}
+
+func TestParserDiagnostics(testing *testing.T) {
+	tests := []struct {
+		input    string
+		code     string
+		message  string
+		expected diagnostic.Span
+	}{
+		{"let x = (1 + 2;", diagnostic.UNEXPECTED_TOKEN, "Expected token ), got ; instead", diagnostic.Span{Line: 1, Position: 14, Length: 1}},
+		{"let x = 0x;", diagnostic.INVALID_LITERAL, "could not parse \"0x\" as integer", diagnostic.Span{Line: 1, Position: 8, Length: 2}},
+		{"let x = \"abc", diagnostic.ILLEGAL_TOKEN, "Unterminated string", diagnostic.Span{Line: 1, Position: 8, Length: 4}},
+		{"let \"a\\tb\" = 1;", diagnostic.UNEXPECTED_TOKEN, "Expected token IDENTIFIER, got STRING instead", diagnostic.Span{Line: 1, Position: 4, Length: 6}},
+		{"let `raw` = 1;", diagnostic.UNEXPECTED_TOKEN, "Expected token IDENTIFIER, got STRING instead", diagnostic.Span{Line: 1, Position: 4, Length: 5}},
+		{"let {null: a} = x;", diagnostic.INVALID_PATTERN, "Invalid pattern \"null\"", diagnostic.Span{Line: 1, Position: 5, Length: 4}},
+		{"1 = 2;", diagnostic.INVALID_ASSIGNMENT, "Invalid assignment target 1", diagnostic.Span{Line: 1, Position: 2, Length: 1}},
+		{"break;", diagnostic.INVALID_STATEMENT, "break statement outside of a loop", diagnostic.Span{Line: 1, Position: 0, Length: 5}},
+		{"f(a: 1, 2);", diagnostic.INVALID_ARGUMENT, "Positional argument 2 after named arguments", diagnostic.Span{Line: 1, Position: 8, Length: 1}},
+	}
+
+	for _, test := range tests {
+		parser := parser.New(newLexer(test.input))
+		parser.Filepath = "main.glass"
+		parser.ParseProgram()
+
+		diagnostics := parser.GetDiagnostics()
+		if len(diagnostics) == 0 {
+			testing.Errorf("no diagnostics for %q", test.input)
+			continue
+		}
+
+		first := diagnostics[0]
+		if first.Severity != diagnostic.ERROR || first.Code != test.code || first.Message != test.message {
+			testing.Errorf(
+				"wrong diagnostic for %q. expected=%s %q, got=%s %s %q",
+				test.input,
+				test.code,
+				test.message,
+				first.Severity,
+				first.Code,
+				first.Message,
+			)
+		}
+
+		if first.File != "main.glass" || first.Span != test.expected {
+			testing.Errorf("wrong location for %q. expected=%+v, got=%s %+v", test.input, test.expected, first.File, first.Span)
+		}
+	}
+}
+
+func newLexer(input string) *lexer.Lexer {
+	return lexer.New(input, func() (string, bool) {
+		return "", true
+	})
+}
+
 func checkParserErrors(t *testing.T, parser *parser.Parser) {
 	errors := parser.GetErrors()
 	if len(errors) == 0 {